@@ -0,0 +1,63 @@
+package typutil_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/KarpelesLab/typutil"
+)
+
+func TestNonStringKeyMapToStruct(t *testing.T) {
+	type Server struct {
+		Host string `json:"host"`
+		Port int    `json:"port"`
+	}
+	type Config struct {
+		Name    string            `json:"name"`
+		Server  Server            `json:"server"`
+		Backups []Server          `json:"backups"`
+		Codes   map[string]string `json:"codes"`
+		Status  string            `json:"200"`
+	}
+
+	// as decoded by YAML or MessagePack libraries
+	tree := map[any]any{
+		"name":   "app",
+		"server": map[any]any{"host": "localhost", "Port": 8080},
+		"backups": []any{
+			map[any]any{"host": "b1", "port": "9000"},
+			map[interface{}]interface{}{"host": "b2"},
+		},
+		"codes": map[any]any{404: "not found"},
+		200:     "ok",
+	}
+
+	cfg, err := typutil.As[Config](tree)
+	if err != nil {
+		t.Errorf("map[any]any to struct failed: %s", err)
+		return
+	}
+	expected := Config{
+		Name:    "app",
+		Server:  Server{Host: "localhost", Port: 8080},
+		Backups: []Server{{Host: "b1", Port: 9000}, {Host: "b2"}},
+		Codes:   map[string]string{"404": "not found"},
+		Status:  "ok",
+	}
+	if !reflect.DeepEqual(cfg, expected) {
+		t.Errorf("unexpected value %+v", cfg)
+	}
+
+	s, err := typutil.As[Server](map[int]string{1: "x"})
+	if err != nil || s != (Server{}) {
+		t.Errorf("unexpected value %+v (err=%v)", s, err)
+	}
+
+	conv := typutil.NewConverter(typutil.DisallowUnknownFields)
+	_, err = typutil.AsWith[Server](conv, map[any]any{"host": "h", 3.5: "x"})
+	var uerr *typutil.UnknownFieldsError
+	if !errors.As(err, &uerr) || !reflect.DeepEqual(uerr.Fields, []string{"3.5"}) {
+		t.Errorf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package typutil_test
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/KarpelesLab/typutil"
+)
+
+func TestAssignSliceToArray(t *testing.T) {
+	var a [4]int
+	err := typutil.Assign(&a, []any{1, "2", 3.0, uint8(4)})
+	if err != nil {
+		t.Errorf("[]any to [4]int failed: %s", err)
+	} else if a != [4]int{1, 2, 3, 4} {
+		t.Errorf("unexpected value %v", a)
+	}
+
+	err = typutil.Assign(&a, []int{1, 2, 3})
+	if !errors.Is(err, typutil.ErrLengthMismatch) {
+		t.Errorf("expected ErrLengthMismatch, got %v", err)
+	}
+}
+
+func TestAssignArrayToSlice(t *testing.T) {
+	s, err := typutil.As[[]string]([3]any{1.5, 2, "3.25"})
+	if err != nil {
+		t.Errorf("[3]any to []string failed: %s", err)
+	} else if len(s) != 3 || s[0] != "1.5" || s[1] != "2" || s[2] != "3.25" {
+		t.Errorf("unexpected value %v", s)
+	}
+
+	s, err = typutil.As[[]string]([3]float64{1.5, 2, 3.25})
+	if err != nil {
+		t.Errorf("[3]float64 to []string failed: %s", err)
+	} else if len(s) != 3 || s[0] != "1.5" || s[1] != "2" || s[2] != "3.25" {
+		t.Errorf("unexpected value %v", s)
+	}
+
+	b, err := typutil.As[[]byte]([4]byte{1, 2, 3, 4})
+	if err != nil {
+		t.Errorf("[4]byte to []byte failed: %s", err)
+	} else if len(b) != 4 || b[3] != 4 {
+		t.Errorf("unexpected value %v", b)
+	}
+}
+
+func TestAssignArrayToArray(t *testing.T) {
+	a, err := typutil.As[[3]float64]([3]int{1, 2, 3})
+	if err != nil {
+		t.Errorf("[3]int to [3]float64 failed: %s", err)
+	} else if a != [3]float64{1, 2, 3} {
+		t.Errorf("unexpected value %v", a)
+	}
+
+	_, err = typutil.As[[2]float64]([3]int{1, 2, 3})
+	if !errors.Is(err, typutil.ErrLengthMismatch) {
+		t.Errorf("expected ErrLengthMismatch, got %v", err)
+	}
+}
+
+func TestAssignStringToByteArray(t *testing.T) {
+	sum := sha256.Sum256([]byte("hello"))
+
+	h, err := typutil.As[[32]byte](hex.EncodeToString(sum[:]))
+	if err != nil {
+		t.Errorf("hex to [32]byte failed: %s", err)
+	} else if h != sum {
+		t.Errorf("unexpected value %x", h)
+	}
+
+	h, err = typutil.As[[32]byte](base64.StdEncoding.EncodeToString(sum[:]))
+	if err != nil {
+		t.Errorf("base64 to [32]byte failed: %s", err)
+	} else if h != sum {
+		t.Errorf("unexpected value %x", h)
+	}
+
+	_, err = typutil.As[[32]byte]("aGVsbG8=")
+	if !errors.Is(err, typutil.ErrLengthMismatch) {
+		t.Errorf("expected ErrLengthMismatch, got %v", err)
+	}
+
+	// byte arrays encode as base64 like byte slices, so the value round-trips
+	s, err := typutil.As[string](sum)
+	if err != nil {
+		t.Errorf("[32]byte to string failed: %s", err)
+	} else if s != base64.StdEncoding.EncodeToString(sum[:]) {
+		t.Errorf("unexpected value %s", s)
+	}
+}
+
+func TestAssignArrayInStruct(t *testing.T) {
+	type Obj struct {
+		Hash   [4]byte
+		Vector [3]float64
+	}
+
+	o, err := typutil.As[Obj](map[string]any{"Hash": "01020304", "Vector": []any{1, 2.5, "3"}})
+	if err != nil {
+		t.Errorf("map to struct with arrays failed: %s", err)
+		return
+	}
+	if o.Hash != [4]byte{1, 2, 3, 4} {
+		t.Errorf("unexpected Hash %v", o.Hash)
+	}
+	if o.Vector != [3]float64{1, 2.5, 3} {
+		t.Errorf("unexpected Vector %v", o.Vector)
+	}
+}
+
+func TestAssignSliceToString(t *testing.T) {
+	// slices and arrays other than bytes cannot become strings
+	var s string
+	if err := typutil.Assign(&s, []int{1, 2}); err == nil {
+		t.Errorf("expected error assigning []int to string, got %q", s)
+	}
+	if err := typutil.Assign(&s, [2]int{1, 2}); err == nil {
+		t.Errorf("expected error assigning [2]int to string, got %q", s)
+	}
+	if err := typutil.Assign(&s, any([]int{1, 2})); err == nil {
+		t.Errorf("expected error assigning any([]int) to string, got %q", s)
+	}
+}
@@ -1,9 +1,10 @@
 package typutil
 
 import (
-	"encoding/base64"
 	"fmt"
+	"math"
 	"reflect"
+	"strconv"
 	"strings"
 	"sync"
 )
@@ -15,8 +16,6 @@ type assignConvType struct {
 	src reflect.Type
 }
 
-var assignFuncCache sync.Map // map[assignConvType]assignFunc
-
 type valueScanner interface {
 	Scan(any) error
 }
@@ -35,13 +34,19 @@ var (
 	valueAssignerType = reflect.TypeFor[AssignableTo]()
 )
 
-func getAssignFunc(dstt reflect.Type, srct reflect.Type) (assignFunc, error) {
-	if dstt == srct {
+func (c *Converter) getAssignFunc(dstt reflect.Type, srct reflect.Type) (assignFunc, error) {
+	if dstt == srct && !c.merges(dstt, srct) {
 		return simpleSet, nil
 	}
 
+	if gen := converterGeneration.Load(); gen != c.cacheGen.Load() {
+		// conversion functions were registered, cached functions may be outdated
+		c.cache.Clear()
+		c.cacheGen.Store(gen)
+	}
+
 	act := assignConvType{dstt, srct}
-	if fi, ok := assignFuncCache.Load(act); ok {
+	if fi, ok := c.cache.Load(act); ok {
 		return fi.(assignFunc), nil
 	}
 
@@ -54,7 +59,7 @@ func getAssignFunc(dstt reflect.Type, srct reflect.Type) (assignFunc, error) {
 	wg.Add(1)
 	defer wg.Done()
 
-	fi, loaded := assignFuncCache.LoadOrStore(act, assignFunc(func(dst, src reflect.Value) error {
+	fi, loaded := c.cache.LoadOrStore(act, assignFunc(func(dst, src reflect.Value) error {
 		wg.Wait()
 		if err != nil {
 			return err
@@ -66,12 +71,12 @@ func getAssignFunc(dstt reflect.Type, srct reflect.Type) (assignFunc, error) {
 	}
 
 	// compute real func
-	f, err = newAssignFunc(dstt, srct)
+	f, err = c.newAssignFunc(dstt, srct)
 	if err != nil {
-		assignFuncCache.Delete(act)
+		c.cache.Delete(act)
 		return nil, err
 	}
-	assignFuncCache.Store(act, f)
+	c.cache.Store(act, f)
 	return f, nil
 }
 
@@ -84,6 +89,12 @@ func getAssignFunc(dstt reflect.Type, srct reflect.Type) (assignFunc, error) {
 // - Slices and maps
 // - Structs (using field names or JSON tags for matching)
 // - Custom types that implement valueScanner or AssignableTo interfaces
+// - Types implementing encoding.TextUnmarshaler, encoding.TextMarshaler or fmt.Stringer
+//
+// When several of these apply, the destination's Scan method is used first,
+// then the source's AssignTo method, then the built-in time.Time and
+// time.Duration handling, then UnmarshalText on the destination and finally
+// MarshalText (or String) on the source.
 //
 // For container types (slices, maps, structs), a shallow copy is performed.
 //
@@ -119,6 +130,12 @@ func getAssignFunc(dstt reflect.Type, srct reflect.Type) (assignFunc, error) {
 // Note that unlike json.Unmarshal or similar functions, Assign requires a pointer
 // to the destination value, not the destination value itself.
 func Assign(dst, src any) error {
+	return defaultConverter.Assign(dst, src)
+}
+
+// Assign sets dst to the value of src using the converter's settings. See the
+// package-level Assign for details.
+func (c *Converter) Assign(dst, src any) error {
 	// grab dst value
 	vdst := reflect.ValueOf(dst)
 	// check if pointer (required)
@@ -132,11 +149,14 @@ func Assign(dst, src any) error {
 	}
 
 	// do the thing
-	f, err := getAssignFunc(vdst.Type(), vsrc.Type())
+	f, err := c.getAssignFunc(vdst.Type(), vsrc.Type())
 	if err != nil {
-		return fmt.Errorf("%w (assigning %T to %T)", err, src, dst)
+		return conversionError(vdst.Type().Elem(), vsrc, fmt.Errorf("%w (assigning %T to %T)", err, src, dst))
+	}
+	if err := f(vdst, vsrc); err != nil {
+		return conversionError(vdst.Type().Elem(), vsrc, err)
 	}
-	return f(vdst, vsrc)
+	return nil
 }
 
 // AssignReflect assigns a value from one reflect.Value to another, with type conversion.
@@ -155,6 +175,12 @@ func Assign(dst, src any) error {
 // This function handles unwrapping interface values, dealing with pointers,
 // and finding the appropriate conversion function for the types involved.
 func AssignReflect(vdst, vsrc reflect.Value) error {
+	return defaultConverter.AssignReflect(vdst, vsrc)
+}
+
+// AssignReflect assigns vsrc to vdst using the converter's settings. See the
+// package-level AssignReflect for details.
+func (c *Converter) AssignReflect(vdst, vsrc reflect.Value) error {
 	if vsrc.Kind() == reflect.Interface {
 		vsrc = vsrc.Elem()
 	}
@@ -169,11 +195,14 @@ func AssignReflect(vdst, vsrc reflect.Value) error {
 		return ErrInvalidSource
 	}
 
-	f, err := getAssignFunc(vdst.Type(), vsrc.Type())
+	f, err := c.getAssignFunc(vdst.Type(), vsrc.Type())
 	if err != nil {
-		return fmt.Errorf("%w (assigning %s to %s)", err, vsrc.Type(), vdst.Type())
+		return conversionError(vdst.Type(), vsrc, fmt.Errorf("%w (assigning %s to %s)", err, vsrc.Type(), vdst.Type()))
+	}
+	if err := f(vdst, vsrc); err != nil {
+		return conversionError(vdst.Type(), vsrc, err)
 	}
-	return f(vdst, vsrc)
+	return nil
 }
 
 // As converts a value to the specified type T, with type conversion as needed.
@@ -205,11 +234,24 @@ func AssignReflect(vdst, vsrc reflect.Value) error {
 //	p := Person{Name: "Alice", Age: 30}
 //	u, err := As[User](p)  // u is User{Name: "Alice", Age: "30"}
 func As[T any](v any) (T, error) {
+	return AsWith[T](defaultConverter, v)
+}
+
+// AsWith converts a value to the specified type T using the given Converter.
+//
+// It behaves like As, except that the converter's options (tag name, parsing
+// policies, validators...) are used instead of the package defaults.
+//
+// Example:
+//
+//	conv := NewConverter(StrictBool)
+//	b, err := AsWith[bool](conv, "maybe") // err != nil
+func AsWith[T any](c *Converter, v any) (T, error) {
 	// convert any type to T
 	typ := reflect.TypeFor[T]()
 	obj := reflect.New(typ) // it's a pointer
 
-	err := AssignReflect(obj, reflect.ValueOf(v))
+	err := c.AssignReflect(obj, reflect.ValueOf(v))
 
 	return obj.Elem().Interface().(T), err
 }
@@ -223,54 +265,113 @@ func ptrCount(t reflect.Type) int {
 	return n
 }
 
-func newAssignFunc(dstt, srct reflect.Type) (assignFunc, error) {
+// newAssignFunc returns a new assignFunc converting srct to dstt, retrying
+// failed conversions through JSON if the converter has JSONFallback
+func (c *Converter) newAssignFunc(dstt, srct reflect.Type) (assignFunc, error) {
+	f, err := c.makeAssignFunc(dstt, srct)
+	if c.jsonFallback && usesJSON(dstt, srct) {
+		return c.makeJSONFallback(dstt, srct, f, err), nil
+	}
+	return f, err
+}
+
+func (c *Converter) makeAssignFunc(dstt, srct reflect.Type) (assignFunc, error) {
 	//log.Printf("assign func lookup %s → %s", srct, dstt)
+	if dstt == genericType {
+		return c.makeAssignGeneric(srct)
+	}
+	if c.merges(dstt, srct) {
+		return c.makeAssignMerge(dstt, srct)
+	}
 	if srct.AssignableTo(dstt) {
 		return simpleSet, nil
 	}
 
+	// registered conversion functions come first
+	if f := c.makeAssignCustom(dstt, srct); f != nil {
+		return f, nil
+	}
+	// registered polymorphic interfaces
+	if f := c.makeAssignPoly(dstt, srct); f != nil {
+		return f, nil
+	}
+
 	srcptrct := ptrCount(srct)
 	dstptrct := ptrCount(dstt)
 	//log.Printf("assign func lookup %s → %s (%d → %d)", srct, dstt, srcptrct, dstptrct)
 
 	// with this we try to adjust src & dst to have the same number of pointer elements so we may have a chance to assign values directly
 	if srcptrct > dstptrct {
-		return ptrReadAndAssign(dstt, srct)
+		return c.ptrReadAndAssign(dstt, srct)
 	} else if dstptrct > 0 {
-		return newNewAndAssign(dstt, srct)
+		return c.newNewAndAssign(dstt, srct)
 	}
 
 	// check for interfaces/etc
 	if reflect.PointerTo(dstt).Implements(valueScannerType) {
-		return makeAssignScanIntf(dstt, srct)
+		return c.makeAssignScanIntf(dstt, srct)
 	}
 	if reflect.PointerTo(srct).Implements(valueAssignerType) {
-		return makeAssignToIntf(dstt, srct)
+		return c.makeAssignToIntf(dstt, srct)
+	}
+
+	// time values
+	if dstt == durationType {
+		return c.makeAssignToDuration(dstt, srct)
+	}
+	if srct == durationType {
+		if f := c.makeAssignFromDuration(dstt, srct); f != nil {
+			return f, nil
+		}
+	}
+	if dstt == timeType {
+		return c.makeAssignToTime(dstt, srct)
+	}
+	if srct == timeType {
+		if f := c.makeAssignFromTime(dstt, srct); f != nil {
+			return f, nil
+		}
+	}
+
+	// text encoding, checked after time values as time.Time implements both interfaces
+	if reflect.PointerTo(dstt).Implements(textUnmarshalerType) && (isTextSource(srct) || srct.Kind() == reflect.Interface) {
+		return c.makeAssignFromText(dstt, srct)
+	}
+	if dstt.Kind() == reflect.String {
+		if f := c.makeAssignToText(dstt, srct); f != nil {
+			return f, nil
+		}
 	}
 
 	switch dstt.Kind() {
 	case reflect.String:
-		return makeAssignToString(dstt, srct), nil
+		return c.makeAssignToString(dstt, srct), nil
 	case reflect.Bool:
-		return makeAssignToBool(dstt, srct), nil
+		return c.makeAssignToBool(dstt, srct), nil
 	case reflect.Float32, reflect.Float64:
-		return makeAssignToFloat(dstt, srct), nil
+		return c.makeAssignToFloat(dstt, srct), nil
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		return makeAssignToInt(dstt, srct), nil
+		return c.makeAssignToInt(dstt, srct), nil
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
-		return makeAssignToUint(dstt, srct), nil
+		return c.makeAssignToUint(dstt, srct), nil
 	case reflect.Slice:
-		return makeAssignToSlice(dstt, srct)
+		return c.makeAssignToSlice(dstt, srct)
+	case reflect.Array:
+		return c.makeAssignToArray(dstt, srct)
 	case reflect.Map:
-		return makeAssignToMap(dstt, srct)
+		return c.makeAssignToMap(dstt, srct)
 	case reflect.Struct:
+		if c.parent != nil {
+			// field specific settings do not apply to the fields of nested structs
+			return c.parent.getAssignFunc(dstt, srct)
+		}
 		switch srct.Kind() {
 		case reflect.Struct:
-			return makeAssignStructToStruct(dstt, srct)
+			return c.makeAssignStructToStruct(dstt, srct)
 		case reflect.Map:
-			return makeAssignMapToStruct(dstt, srct)
+			return c.makeAssignMapToStruct(dstt, srct)
 		case reflect.Interface:
-			return makeAssignAnyToRuntime(dstt, srct), nil
+			return c.makeAssignAnyToRuntime(dstt, srct), nil
 		}
 	}
 
@@ -284,163 +385,258 @@ func simpleSet(dst, src reflect.Value) error {
 }
 
 type assignStructInOut struct {
-	in, out int
+	in, out []int
 	set     assignFunc
+	name    string
+	n       int                        // position of the field
+	def     defaultFunc                // sets the default value, if any
+	goName  string                     // Go name of the field
+	omit    func(v reflect.Value) bool // reports if the value is omitted from maps
 }
 
-type fieldInfo struct {
-	reflect.StructField
-	idx int
-}
-
-func makeAssignStructToStruct(dstt, srct reflect.Type) (assignFunc, error) {
+func (c *Converter) makeAssignStructToStruct(dstt, srct reflect.Type) (assignFunc, error) {
 	var fields []*assignStructInOut
 
 	fieldsIn := make(map[string]*fieldInfo)
-	for i, m := 0, srct.NumField(); i < m; i++ {
-		f := srct.Field(i)
-		if !f.IsExported() {
-			// skip non-exported fields
-			continue
-		}
-		name := f.Name
-		if jsonTag := f.Tag.Get("json"); jsonTag != "" {
-			// check if json tag renames field
-			if jsonTag[0] == '-' {
-				continue
-			}
-			if jsonTag[0] != ',' {
-				jsonA := strings.Split(jsonTag, ",")
-				name = jsonA[0]
-			}
-		}
-		fieldsIn[name] = &fieldInfo{f, i}
+	for _, f := range c.structFields(srct) {
+		fieldsIn[f.name] = f
 	}
-	for i, m := 0, dstt.NumField(); i < m; i++ {
-		dstf := dstt.Field(i)
-		if !dstf.IsExported() {
-			// skip non-exported fields
-			continue
-		}
-		name := dstf.Name
-		if jsonTag := dstf.Tag.Get("json"); jsonTag != "" {
-			// check if json tag renames field
-			if jsonTag[0] == '-' {
-				continue
-			}
-			if jsonTag[0] != ',' {
-				jsonA := strings.Split(jsonTag, ",")
-				name = jsonA[0]
-			}
-		}
-		srcf, ok := fieldsIn[name]
+	for _, dstf := range c.structFields(dstt) {
+		srcf, ok := fieldsIn[dstf.name]
 		if !ok {
 			continue
 		}
 
-		fnc, err := newAssignFunc(dstf.Type, srcf.StructField.Type)
+		fc, err := c.fieldConverter(dstf.StructField, srcf.StructField)
+		if err != nil {
+			return nil, pathBuildError(dstf.name, dstf.Type, srcf.Type, err)
+		}
+		fnc, err := fc.newAssignFunc(dstf.Type, srcf.Type)
 		if fnc == nil {
-			return nil, err
+			return nil, pathBuildError(dstf.name, dstf.Type, srcf.Type, err)
 		}
 
 		fields = append(fields, &assignStructInOut{
-			in:  srcf.idx,
-			out: i,
-			set: fnc,
+			in:   srcf.Index,
+			out:  dstf.Index,
+			set:  fnc,
+			name: dstf.name,
 		})
 	}
 
 	fieldsIn = nil
 
-	validator := getValidatorForType(dstt)
+	validator := c.getValidatorForType(dstt)
 
 	f := func(dst, src reflect.Value) error {
+		ec := &errorCollector{c: c}
 		for _, f := range fields {
-			dstf := dst.Field(f.out)
-			if err := f.set(dstf, src.Field(f.in)); err != nil {
+			srcf, ok := fieldByIndex(src, f.in)
+			if !ok {
+				// nil embedded pointer in source
+				continue
+			}
+			dstf, err := fieldByIndexAlloc(dst, f.out)
+			if err != nil {
 				return err
 			}
+			if err := f.set(dstf, srcf); err != nil {
+				if err := ec.add(f.name, dstf, srcf, err); err != nil {
+					return err
+				}
+			}
 		}
-		if err := validator.validate(dst); err != nil {
+		if err := ec.validate(validator, dst); err != nil {
 			return err
 		}
-		return nil
+		return ec.result()
 	}
 	return f, nil
 }
 
-func makeAssignMapToStruct(dstt, srct reflect.Type) (assignFunc, error) {
-	// srct is a map
-	switch srct.Key().Kind() {
-	case reflect.String:
-		// we index dstt's fields by string
-		fields := make(map[string]*assignStructInOut)
-		mapvtype := srct.Elem()
-
-		for i := 0; i < dstt.NumField(); i++ {
-			f := dstt.Field(i)
-			if !f.IsExported() {
-				// skip non-exported fields
-				continue
-			}
-			fnc, err := newAssignFunc(f.Type, mapvtype)
-			if err != nil {
-				return nil, err
-			}
-			name := f.Name
-			if jsonTag := f.Tag.Get("json"); jsonTag != "" {
-				// check if json tag renames field
-				if jsonTag[0] == '-' {
-					continue
-				}
-				if jsonTag[0] != ',' {
-					jsonA := strings.Split(jsonTag, ",")
-					name = jsonA[0]
-				}
-			}
-			fields[name] = &assignStructInOut{out: i, set: fnc}
+func (c *Converter) makeAssignMapToStruct(dstt, srct reflect.Type) (assignFunc, error) {
+	// srct is a map, keys of other kinds than string (such as the any keys of
+	// decoded YAML trees) are converted with AsString
+	stringKeys := srct.Key().Kind() == reflect.String
+
+	// we index dstt's fields by string, and by lowercase string for case
+	// insensitive matches
+	fields := make(map[string]*assignStructInOut)
+	foldFields := make(map[string]*assignStructInOut)
+	var defaults []*assignStructInOut // fields with a default value
+	var required []*assignStructInOut // fields that must be present
+	setIndex := fieldSetIndex(dstt)
+	mapvtype := srct.Elem()
+
+	for n, f := range c.structFields(dstt) {
+		fc, err := c.fieldConverter(f.StructField)
+		if err != nil {
+			return nil, pathBuildError(f.name, f.Type, mapvtype, err)
+		}
+		fnc, err := fc.newAssignFunc(f.Type, mapvtype)
+		if err != nil {
+			return nil, pathBuildError(f.name, f.Type, mapvtype, err)
+		}
+		def, err := c.makeFieldDefault(f.StructField)
+		if err != nil {
+			return nil, pathBuildError(f.name, f.Type, stringType, err)
+		}
+		fio := &assignStructInOut{out: f.Index, set: fnc, name: f.name, n: n, def: def, goName: f.Name}
+		if def != nil {
+			defaults = append(defaults, fio)
+		}
+		if f.required {
+			required = append(required, fio)
+		}
+		fields[f.name] = fio
+		if fold := strings.ToLower(f.name); !c.caseSensitive && foldFields[fold] == nil {
+			// like encoding/json, the first field in order wins
+			foldFields[fold] = fio
 		}
+	}
 
-		validator := getValidatorForType(dstt)
+	validator := c.getValidatorForType(dstt)
 
-		f := func(dst, src reflect.Value) error {
-			iter := src.MapRange()
-			for iter.Next() {
-				f, ok := fields[iter.Key().String()]
+	f := func(dst, src reflect.Value) error {
+		ec := &errorCollector{c: c}
+		var present []bool
+		if len(defaults) > 0 || len(required) > 0 || setIndex != nil {
+			present = make([]bool, len(fields))
+		}
+		var set FieldSet
+		if setIndex != nil {
+			set = make(FieldSet)
+		}
+		iter := src.MapRange()
+		for iter.Next() {
+			var key string
+			if stringKeys {
+				key = iter.Key().String()
+			} else if k, ok := AsString(iter.Key().Interface()); ok {
+				key = k
+			} else {
+				if c.disallowUnknown {
+					ec.unknown = append(ec.unknown, fmt.Sprint(iter.Key()))
+				}
+				continue
+			}
+			f, ok := fields[key]
+			if !ok {
+				f, ok = foldFields[strings.ToLower(key)]
 				if !ok {
+					if c.disallowUnknown {
+						ec.unknown = append(ec.unknown, key)
+					}
+					continue
+				}
+				if mapHasKey(src, f.name) {
+					// an exact match exists and takes priority
 					continue
 				}
-				dstf := dst.Field(f.out)
-				if err := f.set(dstf, iter.Value()); err != nil {
+			}
+			if present != nil {
+				present[f.n] = true
+			}
+			if set != nil {
+				set[f.goName] = struct{}{}
+			}
+			dstf, err := fieldByIndexAlloc(dst, f.out)
+			if err != nil {
+				return err
+			}
+			if f.def != nil && c.defaultOnNull && isNull(iter.Value()) {
+				// the default value is used instead
+				err = f.def(dstf)
+			} else {
+				err = f.set(dstf, iter.Value())
+			}
+			if err != nil {
+				if err := ec.add(key, dstf, iter.Value(), err); err != nil {
 					return err
 				}
 			}
-			if err := validator.validate(dst); err != nil {
+		}
+		for _, f := range required {
+			if present[f.n] {
+				continue
+			}
+			err := &ConversionError{Dst: dstt.FieldByIndex(f.out).Type, Reason: ReasonMissing, Err: ErrMissingField}
+			if err := ec.add(f.name, dst, reflect.Value{}, err); err != nil {
 				return err
 			}
-			return nil
 		}
-		return f, nil
-	default:
-		// unsupported map type
-		return nil, fmt.Errorf("%w: invalid src type %s", ErrAssignImpossible, srct)
+		if set != nil {
+			dst.FieldByIndex(setIndex).Set(reflect.ValueOf(set))
+		}
+		for _, f := range defaults {
+			if present[f.n] {
+				continue
+			}
+			dstf, err := fieldByIndexAlloc(dst, f.out)
+			if err != nil {
+				return err
+			}
+			if c.merge && !dstf.IsZero() {
+				// keep the value set by a previous merge
+				continue
+			}
+			if err := f.def(dstf); err != nil {
+				if err := ec.add(f.name, dstf, reflect.Value{}, err); err != nil {
+					return err
+				}
+			}
+		}
+		if err := ec.validate(validator, dst); err != nil {
+			return err
+		}
+		return ec.result()
+	}
+	return f, nil
+}
+
+// mapHasKey returns true if the map m has a key equal to name once converted to
+// a string
+func mapHasKey(m reflect.Value, name string) bool {
+	_, ok := mapLookup(m, name)
+	return ok
+}
+
+// mapLookup returns the value of the key of m equal to name once converted to
+// a string
+func mapLookup(m reflect.Value, name string) (reflect.Value, bool) {
+	kt := m.Type().Key()
+	if kt.Kind() == reflect.String {
+		v := m.MapIndex(reflect.ValueOf(name).Convert(kt))
+		return v, v.IsValid()
+	}
+	iter := m.MapRange()
+	for iter.Next() {
+		if k, ok := AsString(iter.Key().Interface()); ok && k == name {
+			return iter.Value(), true
+		}
 	}
+	return reflect.Value{}, false
 }
 
-func makeAssignAnyToRuntime(dstt, srct reflect.Type) assignFunc {
+func (c *Converter) makeAssignAnyToRuntime(dstt, srct reflect.Type) assignFunc {
 	return func(dst, src reflect.Value) error {
-		return AssignReflect(dst, src)
+		return c.AssignReflect(dst, src)
 	}
 }
 
-func newNewAndAssign(dstt, srct reflect.Type) (assignFunc, error) {
+func (c *Converter) newNewAndAssign(dstt, srct reflect.Type) (assignFunc, error) {
 	subt := dstt.Elem()
-	subf, err := newAssignFunc(subt, srct)
+	subf, err := c.newAssignFunc(subt, srct)
 	if err != nil {
 		return nil, err
 	}
 
 	f := func(dst, src reflect.Value) error {
+		if src.Kind() == reflect.Interface && src.IsNil() {
+			// nil (such as a JSON null) resets the pointer
+			dst.SetZero()
+			return nil
+		}
 		if dst.IsNil() {
 			dst.Set(reflect.New(subt))
 		}
@@ -449,9 +645,9 @@ func newNewAndAssign(dstt, srct reflect.Type) (assignFunc, error) {
 	return f, nil
 }
 
-func ptrReadAndAssign(dstt, srct reflect.Type) (assignFunc, error) {
+func (c *Converter) ptrReadAndAssign(dstt, srct reflect.Type) (assignFunc, error) {
 	subt := srct.Elem()
-	subf, err := newAssignFunc(dstt, subt)
+	subf, err := c.newAssignFunc(dstt, subt)
 	if err != nil {
 		return nil, err
 	}
@@ -465,28 +661,44 @@ func ptrReadAndAssign(dstt, srct reflect.Type) (assignFunc, error) {
 	return f, nil
 }
 
-func makeAssignToString(dstt, srct reflect.Type) assignFunc {
+func (c *Converter) makeAssignToString(dstt, srct reflect.Type) assignFunc {
 	switch srct.Kind() {
 	case reflect.String:
 		return func(dst, src reflect.Value) error {
-			dst.Set(src)
+			dst.SetString(src.String())
 			return nil
 		}
-	case reflect.Slice:
+	case reflect.Interface:
+		// perform this runtime
+		return c.makeAssignAnyToRuntime(dstt, srct)
+	case reflect.Float32, reflect.Float64:
+		bits := srct.Bits()
+		return func(dst, src reflect.Value) error {
+			dst.SetString(formatFloat(src.Float(), bits))
+			return nil
+		}
+	case reflect.Slice, reflect.Array:
 		if srct.Elem().Kind() == reflect.Uint8 {
-			// encode to base64
+			// encode to base64, or the configured encoding
+			if srct.Kind() == reflect.Array {
+				return func(dst, src reflect.Value) error {
+					dst.SetString(c.bytesEncoding.encode(arrayBytes(src)))
+					return nil
+				}
+			}
 			return func(dst, src reflect.Value) error {
-				dst.SetString(base64.StdEncoding.EncodeToString(src.Bytes()))
+				dst.SetString(c.bytesEncoding.encode(src.Bytes()))
 				return nil
 			}
 		}
+		// other slices and arrays fail with an error in AsString
 		fallthrough
 	default:
 		// perform runtime conversion
 		return func(dst, src reflect.Value) error {
 			str, ok := AsString(src.Interface())
 			if !ok {
-				return fmt.Errorf("failed to convert %s to string", src.Type())
+				return cannotConvert(src, dstt)
 			}
 			dst.SetString(str)
 			return nil
@@ -494,125 +706,171 @@ func makeAssignToString(dstt, srct reflect.Type) assignFunc {
 	}
 }
 
-func makeAssignToSlice(dstt, srct reflect.Type) (assignFunc, error) {
-	if dstt.Elem().Kind() == reflect.Uint8 {
+func (c *Converter) makeAssignToSlice(dstt, srct reflect.Type) (assignFunc, error) {
+	if dstt.Elem().Kind() == reflect.Uint8 && srct.Kind() != reflect.Slice && srct.Kind() != reflect.Array {
 		// []byte = possibly a string
-		return makeAssignToByteSlice(dstt, srct)
+		return c.makeAssignToByteSlice(dstt, srct)
 	}
 
 	switch srct.Kind() {
-	case reflect.Slice:
-		// slice→slice
-		convfunc, err := getAssignFunc(dstt.Elem(), srct.Elem())
+	case reflect.Slice, reflect.Array:
+		// slice→slice or array→slice
+		convfunc, err := c.getAssignFunc(dstt.Elem(), srct.Elem())
 		if err != nil {
 			return nil, err
 		}
 
 		f := func(dst, src reflect.Value) error {
 			ln := src.Len()
-			if dst.Cap() < ln {
-				dst.Grow(ln - dst.Cap())
+			off := 0
+			if c.mergeAppend {
+				// new elements go after the existing ones
+				off = dst.Len()
+			}
+			if dst.Cap() < off+ln {
+				dst.Grow(off + ln - dst.Len())
 			}
-			dst.SetLen(ln)
+			dst.SetLen(off + ln)
 			//dst.Set(reflect.MakeSlice(dstt.Elem(), ln, ln))
+			ec := &errorCollector{c: c}
 			for i := 0; i < ln; i++ {
-				if err := convfunc(dst.Index(i), src.Index(i)); err != nil {
-					return err
+				if err := convfunc(dst.Index(off+i), src.Index(i)); err != nil {
+					if err := ec.add(fmt.Sprintf("[%d]", off+i), dst.Index(off+i), src.Index(i), err); err != nil {
+						return err
+					}
 				}
 			}
-			return nil
+			return ec.result()
 		}
 		return f, nil
 	case reflect.Interface:
 		// perform this runtime
-		return makeAssignAnyToRuntime(dstt, srct), nil
+		return c.makeAssignAnyToRuntime(dstt, srct), nil
 	default:
 		return nil, fmt.Errorf("%w: invalid source %s", ErrAssignImpossible, srct.Kind())
 	}
 }
 
-func makeAssignToByteSlice(dstt, srct reflect.Type) (assignFunc, error) {
+func (c *Converter) makeAssignToArray(dstt, srct reflect.Type) (assignFunc, error) {
 	switch srct.Kind() {
+	case reflect.Slice, reflect.Array:
+		if srct.Kind() == reflect.Array && srct.Len() != dstt.Len() {
+			return nil, fmt.Errorf("%w: cannot assign %s to %s", ErrLengthMismatch, srct, dstt)
+		}
+		convfunc, err := c.getAssignFunc(dstt.Elem(), srct.Elem())
+		if err != nil {
+			return nil, err
+		}
+
+		f := func(dst, src reflect.Value) error {
+			ln := src.Len()
+			if ln != dst.Len() {
+				return fmt.Errorf("%w: expected %d elements, got %d", ErrLengthMismatch, dst.Len(), ln)
+			}
+			ec := &errorCollector{c: c}
+			for i := 0; i < ln; i++ {
+				if err := convfunc(dst.Index(i), src.Index(i)); err != nil {
+					if err := ec.add(fmt.Sprintf("[%d]", i), dst.Index(i), src.Index(i), err); err != nil {
+						return err
+					}
+				}
+			}
+			return ec.result()
+		}
+		return f, nil
 	case reflect.String:
-		// assume base64 encoded
+		if dstt.Elem().Kind() != reflect.Uint8 {
+			break
+		}
+		// [N]byte = hex or base64 encoded string, or the configured encoding
 		f := func(dst, src reflect.Value) error {
-			dec, err := base64.StdEncoding.DecodeString(src.String())
+			dec, err := c.bytesEncoding.decodeFixed(src.String(), dst.Len())
 			if err != nil {
 				return err
 			}
-			dst.SetBytes(dec)
+			for i, b := range dec {
+				dst.Index(i).SetUint(uint64(b))
+			}
 			return nil
 		}
 		return f, nil
 	case reflect.Interface:
 		// perform this runtime
-		return makeAssignAnyToRuntime(dstt, srct), nil
-	default:
-		return nil, fmt.Errorf("%w: unsupported type %s to byte slice", ErrAssignImpossible, srct)
+		return c.makeAssignAnyToRuntime(dstt, srct), nil
 	}
+	return nil, fmt.Errorf("%w: unsupported type %s to array", ErrAssignImpossible, srct)
 }
 
-func makeAssignToFloat(dstt, srct reflect.Type) assignFunc {
-	switch srct.Kind() {
-	case reflect.Float32, reflect.Float64:
-		return func(dst, src reflect.Value) error {
-			dst.Set(src)
-			return nil
-		}
-	default:
-		// perform runtime conversion
-		return func(dst, src reflect.Value) error {
-			v, ok := AsFloat(src.Interface())
-			if !ok {
-				return fmt.Errorf("failed to convert %s to float", src.Type())
-			}
-			dst.SetFloat(v)
-			return nil
-		}
+// formatFloat formats f the same way encoding/json would
+func formatFloat(f float64, bits int) string {
+	abs := math.Abs(f)
+	if abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		return strconv.FormatFloat(f, 'e', -1, bits)
+	}
+	return strconv.FormatFloat(f, 'f', -1, bits)
+}
+
+// arrayBytes returns the content of a byte array value, which may not be addressable
+func arrayBytes(v reflect.Value) []byte {
+	res := make([]byte, v.Len())
+	for i := range res {
+		res[i] = byte(v.Index(i).Uint())
 	}
+	return res
 }
 
-func makeAssignToInt(dstt, srct reflect.Type) assignFunc {
+func (c *Converter) makeAssignToByteSlice(dstt, srct reflect.Type) (assignFunc, error) {
 	switch srct.Kind() {
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		return func(dst, src reflect.Value) error {
-			dst.Set(src)
+	case reflect.String:
+		// assume base64 encoded, unless another encoding is configured
+		f := func(dst, src reflect.Value) error {
+			dec, err := c.bytesEncoding.decode(src.String())
+			if err != nil {
+				return err
+			}
+			dst.SetBytes(dec)
 			return nil
 		}
+		return f, nil
+	case reflect.Interface:
+		// perform this runtime
+		return c.makeAssignAnyToRuntime(dstt, srct), nil
 	default:
-		// perform runtime conversion
-		return func(dst, src reflect.Value) error {
-			v, ok := AsInt(src.Interface())
-			if !ok {
-				return fmt.Errorf("failed to convert %s to int", src.Type())
-			}
-			dst.SetInt(v)
-			return nil
+		return nil, fmt.Errorf("%w: unsupported type %s to byte slice", ErrAssignImpossible, srct)
+	}
+}
+
+func (c *Converter) makeAssignToFloat(dstt, srct reflect.Type) assignFunc {
+	return func(dst, src reflect.Value) error {
+		n, ok := c.numberOf(src)
+		if !ok {
+			return cannotConvert(src, dstt)
 		}
+		return c.setFloat(dst, n)
 	}
 }
 
-func makeAssignToUint(dstt, srct reflect.Type) assignFunc {
-	switch srct.Kind() {
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
-		return func(dst, src reflect.Value) error {
-			dst.Set(src)
-			return nil
+func (c *Converter) makeAssignToInt(dstt, srct reflect.Type) assignFunc {
+	return func(dst, src reflect.Value) error {
+		n, ok := c.numberOf(src)
+		if !ok {
+			return cannotConvert(src, dstt)
 		}
-	default:
-		// perform runtime conversion
-		return func(dst, src reflect.Value) error {
-			v, ok := AsUint(src.Interface())
-			if !ok {
-				return fmt.Errorf("failed to convert %s to int", src.Type())
-			}
-			dst.SetUint(v)
-			return nil
+		return c.setInt(dst, n)
+	}
+}
+
+func (c *Converter) makeAssignToUint(dstt, srct reflect.Type) assignFunc {
+	return func(dst, src reflect.Value) error {
+		n, ok := c.numberOf(src)
+		if !ok {
+			return cannotConvert(src, dstt)
 		}
+		return c.setUint(dst, n)
 	}
 }
 
-func makeAssignToBool(dstt, srct reflect.Type) assignFunc {
+func (c *Converter) makeAssignToBool(dstt, srct reflect.Type) assignFunc {
 	switch srct.Kind() {
 	case reflect.Bool:
 		return func(dst, src reflect.Value) error {
@@ -622,88 +880,151 @@ func makeAssignToBool(dstt, srct reflect.Type) assignFunc {
 	default:
 		// perform runtime conversion
 		return func(dst, src reflect.Value) error {
-			dst.SetBool(AsBool(src.Interface()))
+			v, ok := c.asBool(src.Interface())
+			if !ok {
+				return cannotConvert(src, dstt)
+			}
+			dst.SetBool(v)
 			return nil
 		}
 	}
 }
 
-func makeAssignToMap(dstt, srct reflect.Type) (assignFunc, error) {
+func (c *Converter) makeAssignToMap(dstt, srct reflect.Type) (assignFunc, error) {
 	switch srct.Kind() {
 	case reflect.Map:
-		kf, err := getAssignFunc(dstt.Key(), srct.Key())
+		kf, err := c.getAssignFunc(dstt.Key(), srct.Key())
 		if err != nil {
 			return nil, err
 		}
-		vf, err := getAssignFunc(dstt.Elem(), srct.Elem())
+		vf, err := c.getAssignFunc(dstt.Elem(), srct.Elem())
 		if err != nil {
 			return nil, err
 		}
 
 		f := func(dst, src reflect.Value) error {
-			dst.Set(reflect.MakeMap(dstt))
+			if !c.merge || dst.IsNil() {
+				dst.Set(reflect.MakeMap(dstt))
+			}
+			ec := &errorCollector{c: c}
 			iter := src.MapRange()
 			for iter.Next() {
 				dk := reflect.New(dstt.Key()).Elem()
 				dv := reflect.New(dstt.Elem()).Elem()
+				seg := fmt.Sprintf("[%v]", iter.Key())
 				if err := kf(dk, iter.Key()); err != nil {
-					return err
+					if err := ec.add(seg, dk, iter.Key(), err); err != nil {
+						return err
+					}
+					continue
+				}
+				if c.merge {
+					// start from the current value, if any
+					if cur := dst.MapIndex(dk); cur.IsValid() {
+						dv.Set(cur)
+					}
 				}
 				if err := vf(dv, iter.Value()); err != nil {
-					return err
+					if err := ec.add(seg, dv, iter.Value(), err); err != nil {
+						return err
+					}
+					continue
 				}
 				dst.SetMapIndex(dk, dv)
 			}
-			return nil
+			return ec.result()
 		}
 		return f, nil
 	case reflect.Struct:
+		if c.parent != nil {
+			// field specific settings do not apply to the fields of nested structs
+			return c.parent.getAssignFunc(dstt, srct)
+		}
 		if dstt.Key().Kind() != reflect.String {
 			// we require map converted from struct to have a string key
 			return nil, fmt.Errorf("%w: map key is not of string type", ErrAssignImpossible)
 		}
 		subt := dstt.Elem()
+		if isGeneric(subt) {
+			// nested values are converted to generic values as well
+			subt = genericType
+		}
 
 		fieldsIn := make(map[string]*assignStructInOut)
-		for i := 0; i < srct.NumField(); i++ {
-			f := srct.Field(i)
-			name := f.Name
-			if jsonTag := f.Tag.Get("json"); jsonTag != "" {
-				// check if json tag renames field
-				if jsonTag[0] == '-' {
-					continue
-				}
-				if jsonTag[0] != ',' {
-					jsonA := strings.Split(jsonTag, ",")
-					name = jsonA[0]
-				}
+		for _, f := range c.structFields(srct) {
+			fc, err := c.fieldConverter(f.StructField)
+			if err != nil {
+				return nil, pathBuildError(f.name, subt, f.Type, err)
+			}
+			fnc, err := fc.getAssignFunc(subt, f.Type)
+			if err != nil {
+				return nil, pathBuildError(f.name, subt, f.Type, err)
+			}
+			fieldsIn[f.name] = &assignStructInOut{in: f.Index, set: fnc, omit: c.makeOmitFunc(f.StructField)}
+		}
+
+		// discriminators of registered polymorphic types
+		var discriminators []reflect.Value
+		for _, n := range c.polyNames(srct) {
+			if _, found := fieldsIn[n.field]; found {
+				continue
+			}
+			k := reflect.ValueOf(n.field).Convert(dstt.Key())
+			v := reflect.New(dstt.Elem()).Elem()
+			nf, err := c.getAssignFunc(dstt.Elem(), stringType)
+			if err == nil {
+				err = nf(v, reflect.ValueOf(n.name))
 			}
-			fnc, err := getAssignFunc(subt, f.Type)
 			if err != nil {
-				return nil, err
+				return nil, pathBuildError(n.field, dstt.Elem(), stringType, err)
 			}
-			fieldsIn[name] = &assignStructInOut{in: i, set: fnc}
+			discriminators = append(discriminators, k, v)
 		}
 
 		f := func(dst, src reflect.Value) error {
-			dst.Set(reflect.MakeMap(dstt))
+			if !c.merge || dst.IsNil() {
+				dst.Set(reflect.MakeMap(dstt))
+			}
+			ec := &errorCollector{c: c}
 			for s, f := range fieldsIn {
+				srcf, ok := fieldByIndex(src, f.in)
+				if !ok {
+					// nil embedded pointer
+					continue
+				}
+				if f.omit != nil && f.omit(srcf) {
+					continue
+				}
 				dv := reflect.New(dstt.Elem()).Elem()
-				if err := f.set(dv, src.Field(f.in)); err != nil {
-					return err
+				if c.merge {
+					if cur := dst.MapIndex(reflect.ValueOf(s)); cur.IsValid() {
+						dv.Set(cur)
+					}
+				}
+				if err := f.set(dv, srcf); err != nil {
+					if err := ec.add(s, dv, srcf, err); err != nil {
+						return err
+					}
+					continue
 				}
 				dst.SetMapIndex(reflect.ValueOf(s), dv)
 			}
-			return nil
+			for i := 0; i < len(discriminators); i += 2 {
+				dst.SetMapIndex(discriminators[i], discriminators[i+1])
+			}
+			return ec.result()
 		}
 		return f, nil
+	case reflect.Interface:
+		// perform this runtime
+		return c.makeAssignAnyToRuntime(dstt, srct), nil
 	default:
 		return nil, fmt.Errorf("%w: unsupported type %s", ErrAssignImpossible, srct)
 	}
 }
 
-func makeAssignScanIntf(dstt, srct reflect.Type) (assignFunc, error) {
-	validator := getValidatorForType(dstt)
+func (c *Converter) makeAssignScanIntf(dstt, srct reflect.Type) (assignFunc, error) {
+	validator := c.getValidatorForType(dstt)
 
 	f := func(dst, src reflect.Value) error {
 		if !dst.CanAddr() {
@@ -714,13 +1035,13 @@ func makeAssignScanIntf(dstt, srct reflect.Type) (assignFunc, error) {
 			return err
 		}
 
-		return validator.validate(dst)
+		return validator.validate(c, dst)
 	}
 	return f, nil
 }
 
-func makeAssignToIntf(dstt, srct reflect.Type) (assignFunc, error) {
-	validator := getValidatorForType(dstt)
+func (c *Converter) makeAssignToIntf(dstt, srct reflect.Type) (assignFunc, error) {
+	validator := c.getValidatorForType(dstt)
 
 	f := func(dst, src reflect.Value) error {
 		if !dst.CanAddr() {
@@ -733,7 +1054,7 @@ func makeAssignToIntf(dstt, srct reflect.Type) (assignFunc, error) {
 			return err
 		}
 
-		return validator.validate(dst)
+		return validator.validate(c, dst)
 	}
 	return f, nil
 }
@@ -90,7 +90,9 @@ func TestAssignStruct(t *testing.T) {
 	a := &objA{A: "hello world", B: 42, C: 123456.789321, D: 555.22}
 	var b *objB
 
-	err := typutil.Assign(&b, a)
+	// C and D are not whole numbers and will be rounded
+	lossy := typutil.NewConverter(typutil.LossyNumbers)
+	err := lossy.Assign(&b, a)
 	if err != nil {
 		t.Errorf("struct to struct assign failed: %s", err)
 		return
@@ -112,7 +114,7 @@ func TestAssignStruct(t *testing.T) {
 	// test assign from map
 	b = nil
 	arg := any(map[string]any{"A": "from a map", "X": 99, "C": 123.456})
-	err = typutil.Assign(&b, &arg)
+	err = lossy.Assign(&b, &arg)
 	if err != nil {
 		t.Errorf("map to struct assign failed: %s", err)
 		return
@@ -357,6 +359,10 @@ func TestAssignToInt(t *testing.T) {
 	t.Run("float to int", func(t *testing.T) {
 		var i int
 		err := typutil.Assign(&i, 3.7)
+		if !errors.Is(err, typutil.ErrPrecisionLoss) {
+			t.Errorf("expected ErrPrecisionLoss, got %v", err)
+		}
+		err = typutil.NewConverter(typutil.LossyNumbers).Assign(&i, 3.7)
 		if err != nil {
 			t.Errorf("unexpected error: %v", err)
 		}
@@ -0,0 +1,109 @@
+package typutil
+
+import (
+	"reflect"
+	"strings"
+)
+
+// CollectErrors is a functional option for NewConverter that makes
+// conversions keep going when the conversion or validation of an element
+// fails, so that every field that can be set is set.
+//
+// When more than one element failed, the returned error is a MultiError
+// holding a *ConversionError (or *UnknownFieldsError) for each of them, which
+// can be inspected with errors.Is and errors.As, or by ranging over it.
+//
+// Example:
+//
+//	conv := typutil.NewConverter(typutil.CollectErrors)
+//	err := conv.Assign(&form, values)
+//	if errs, ok := err.(typutil.MultiError); ok {
+//		for _, e := range errs {
+//			// report e
+//		}
+//	}
+func CollectErrors(c *Converter) {
+	c.collectErrors = true
+}
+
+// MultiError is returned when CollectErrors is set and a conversion failed
+// for more than one element. Like errors returned by errors.Join, it
+// implements Unwrap() []error.
+type MultiError []error
+
+// Error returns the messages of all errors, separated by newlines.
+func (e MultiError) Error() string {
+	msgs := make([]string, len(e))
+	for n, err := range e {
+		msgs[n] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the errors held by e.
+func (e MultiError) Unwrap() []error {
+	return e
+}
+
+// errorCollector accumulates the errors of the elements of a container
+// (struct, map, slice...) during a conversion
+type errorCollector struct {
+	c       *Converter
+	unknown []string
+	errs    []error
+}
+
+// add records err, which happened on the element at segment, and returns the
+// error the conversion must stop with, if any
+func (ec *errorCollector) add(segment string, dst, src reflect.Value, err error) error {
+	if errs, ok := err.(MultiError); ok {
+		// only happens when collecting errors
+		for _, e := range errs {
+			ec.add(segment, dst, src, e)
+		}
+		return nil
+	}
+
+	var ok bool
+	if ec.unknown, ok = appendUnknown(ec.unknown, segment, err); ok {
+		// keep going to report all unknown fields
+		return nil
+	}
+	err = pathError(segment, dst, src, err)
+	if !ec.c.collectErrors {
+		return err
+	}
+	ec.errs = append(ec.errs, err)
+	return nil
+}
+
+// validate runs validator on the struct dst. Unless errors are collected,
+// this only happens if no error was found so far.
+func (ec *errorCollector) validate(validator structValidator, dst reflect.Value) error {
+	if !ec.c.collectErrors {
+		if err := ec.result(); err != nil {
+			return err
+		}
+		return validator.validate(ec.c, dst)
+	}
+	if err := validator.validate(ec.c, dst); err != nil {
+		ec.add("", dst, reflect.Value{}, err)
+	}
+	return nil
+}
+
+// result returns the errors found, if any
+func (ec *errorCollector) result() error {
+	errs := ec.errs
+	if err := unknownError(ec.unknown); err != nil {
+		errs = append(errs, err)
+	}
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	default:
+		return MultiError(errs)
+	}
+}
@@ -0,0 +1,76 @@
+package typutil_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/KarpelesLab/typutil"
+)
+
+func TestCollectErrors(t *testing.T) {
+	type Line struct {
+		Qty uint8
+	}
+	type Form struct {
+		Name  string `validator:"minlength=3"`
+		Age   int    `json:"age"`
+		Email string `validator:"not_empty"`
+		Lines []Line
+		Notes string
+	}
+
+	src := map[string]any{
+		"Name":  "Al",
+		"age":   "old",
+		"Email": "",
+		"Lines": []any{map[string]any{"Qty": 1}, map[string]any{"Qty": 1000}, map[string]any{"Qty": -1}},
+		"Notes": "kept",
+	}
+
+	// by default, the first error is returned
+	_, err := typutil.As[Form](src)
+	if _, ok := err.(typutil.MultiError); ok || err == nil {
+		t.Errorf("expected a single error, got %v", err)
+	}
+
+	conv := typutil.NewConverter(typutil.CollectErrors)
+	f, err := typutil.AsWith[Form](conv, src)
+	errs, ok := err.(typutil.MultiError)
+	if !ok {
+		t.Errorf("expected MultiError, got %T %v", err, err)
+		return
+	}
+
+	var paths []string
+	for _, e := range errs {
+		var cerr *typutil.ConversionError
+		if !errors.As(e, &cerr) {
+			t.Errorf("expected *ConversionError, got %v", e)
+			continue
+		}
+		paths = append(paths, cerr.Path+":"+cerr.Reason.String())
+	}
+	slices.Sort(paths)
+	expected := []string{"Email:validation", "Lines[1].Qty:overflow", "Lines[2].Qty:overflow", "Name:validation", "age:syntax"}
+	if !slices.Equal(paths, expected) {
+		t.Errorf("unexpected errors %v, expected %v", paths, expected)
+	}
+
+	// fields that could be converted are set
+	if f.Name != "Al" || f.Notes != "kept" || len(f.Lines) != 3 || f.Lines[0].Qty != 1 {
+		t.Errorf("unexpected value %+v", f)
+	}
+
+	// the multi-error works with errors.Is and errors.As
+	if !errors.Is(err, typutil.ErrOverflow) || !errors.Is(err, typutil.ErrSyntax) {
+		t.Errorf("expected errors.Is to find wrapped errors")
+	}
+
+	// a single error is not wrapped in a MultiError
+	_, err = typutil.AsWith[Form](conv, map[string]any{"Name": "Alice", "Email": "a@example.com", "age": "x"})
+	var cerr *typutil.ConversionError
+	if !errors.As(err, &cerr) || cerr.Path != "age" {
+		t.Errorf("expected a single *ConversionError, got %v", err)
+	}
+}
@@ -0,0 +1,193 @@
+package typutil
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ConversionReason describes why a conversion failed.
+type ConversionReason int
+
+const (
+	ReasonOther         ConversionReason = iota // any other error, such as one returned by UnmarshalText
+	ReasonUnsupported                           // no conversion exists between the types (ErrAssignImpossible)
+	ReasonSyntax                                // the source could not be parsed (ErrSyntax)
+	ReasonOverflow                              // the value is out of range (ErrOverflow)
+	ReasonPrecisionLoss                         // the value cannot be represented exactly (ErrPrecisionLoss)
+	ReasonNilPointer                            // the source is nil or a nil pointer (ErrNilPointerRead, ErrInvalidSource)
+	ReasonLength                                // the number of elements does not match (ErrLengthMismatch)
+	ReasonValidation                            // a validator rejected the value
+	ReasonMissing                               // a required field is missing (ErrMissingField)
+)
+
+// String returns a short name for the reason.
+func (r ConversionReason) String() string {
+	switch r {
+	case ReasonUnsupported:
+		return "unsupported"
+	case ReasonSyntax:
+		return "syntax"
+	case ReasonOverflow:
+		return "overflow"
+	case ReasonPrecisionLoss:
+		return "precision loss"
+	case ReasonNilPointer:
+		return "nil pointer"
+	case ReasonLength:
+		return "length mismatch"
+	case ReasonValidation:
+		return "validation"
+	case ReasonMissing:
+		return "missing"
+	default:
+		return "other"
+	}
+}
+
+// reasonOf returns the reason matching the sentinel error wrapped by err
+func reasonOf(err error) ConversionReason {
+	var verr *validationError
+	switch {
+	case errors.As(err, &verr):
+		return ReasonValidation
+	case errors.Is(err, ErrOverflow):
+		return ReasonOverflow
+	case errors.Is(err, ErrPrecisionLoss):
+		return ReasonPrecisionLoss
+	case errors.Is(err, ErrSyntax):
+		return ReasonSyntax
+	case errors.Is(err, ErrNilPointerRead), errors.Is(err, ErrInvalidSource):
+		return ReasonNilPointer
+	case errors.Is(err, ErrLengthMismatch):
+		return ReasonLength
+	case errors.Is(err, ErrMissingField):
+		return ReasonMissing
+	case errors.Is(err, ErrAssignImpossible):
+		return ReasonUnsupported
+	default:
+		return ReasonOther
+	}
+}
+
+// ConversionError is returned when a conversion fails. It records where the
+// failure happened, what was being converted and why.
+//
+// ConversionError wraps the underlying error, so errors.Is can still be used
+// to check for errors such as ErrOverflow. Errors returned by validators or by
+// methods such as UnmarshalText are only wrapped in a ConversionError when
+// they happen in a nested value or when errors are collected.
+//
+// Example:
+//
+//	var order Order
+//	err := typutil.Assign(&order, payload)
+//	var cerr *typutil.ConversionError
+//	if errors.As(err, &cerr) {
+//		log.Printf("invalid value %v at %s", cerr.Value, cerr.Path) // invalid value abc at items[3].price
+//	}
+type ConversionError struct {
+	Path   string           // path to the failing element, such as "items[3].price", empty at the top level
+	Value  any              // source value, nil if not available
+	Src    reflect.Type     // type of the source value
+	Dst    reflect.Type     // type of the destination
+	Reason ConversionReason // why the conversion failed
+	Err    error            // underlying error
+}
+
+// Error returns the underlying error message, prefixed with the path.
+func (e *ConversionError) Error() string {
+	if e.Path == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s: %s", e.Path, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ConversionError) Unwrap() error {
+	return e.Err
+}
+
+// newConversionError returns a *ConversionError for converting src to dstt.
+// src may be the zero Value if it is not known.
+func newConversionError(dstt reflect.Type, src reflect.Value, err error) *ConversionError {
+	if src.Kind() == reflect.Interface && !src.IsNil() {
+		// report the actual value being converted
+		src = src.Elem()
+	}
+	res := &ConversionError{Dst: dstt, Reason: reasonOf(err), Err: err}
+	if src.IsValid() {
+		res.Src = src.Type()
+		if src.CanInterface() {
+			res.Value = src.Interface()
+		}
+	}
+	return res
+}
+
+// conversionError returns err as returned by a top level conversion: errors
+// caused by the conversion itself are wrapped in a *ConversionError, other
+// errors (such as validation errors) are returned as is
+func conversionError(dstt reflect.Type, src reflect.Value, err error) error {
+	var cerr *ConversionError
+	if errors.As(err, &cerr) {
+		// already has details, possibly for a nested value
+		return err
+	}
+	if _, ok := err.(*UnknownFieldsError); ok {
+		return err
+	}
+	switch reasonOf(err) {
+	case ReasonOther, ReasonValidation:
+		return err
+	}
+	return newConversionError(dstt, src, err)
+}
+
+// cannotConvert returns the error for a value of src that cannot be converted
+// to dstt: strings that cannot be parsed cause ErrSyntax, other types
+// ErrAssignImpossible
+func cannotConvert(src reflect.Value, dstt reflect.Type) error {
+	if src.Kind() == reflect.Interface && !src.IsNil() {
+		src = src.Elem()
+	}
+	switch {
+	case !src.IsValid():
+		return fmt.Errorf("%w: cannot convert nil to %s", ErrInvalidSource, dstt)
+	case src.Kind() == reflect.String:
+		return fmt.Errorf("%w: cannot convert %q to %s", ErrSyntax, src.String(), dstt)
+	case src.Kind() == reflect.Slice && src.Type().Elem().Kind() == reflect.Uint8:
+		return fmt.Errorf("%w: cannot convert %q to %s", ErrSyntax, src.Bytes(), dstt)
+	}
+	return fmt.Errorf("%w: cannot convert %s to %s", ErrAssignImpossible, src.Type(), dstt)
+}
+
+// pathError returns err for the element at segment of a container: the path
+// of a *ConversionError is prefixed with segment, and other errors are wrapped
+// in a *ConversionError for that element.
+func pathError(segment string, dst, src reflect.Value, err error) error {
+	switch e := err.(type) {
+	case *ConversionError:
+		res := *e
+		res.Path = joinPath(segment, e.Path)
+		return &res
+	case *UnknownFieldsError:
+		// already carries paths, and handled by appendUnknown
+		return err
+	}
+
+	res := newConversionError(dst.Type(), src, err)
+	res.Path = segment
+	return res
+}
+
+// pathBuildError is like pathError for errors happening while building
+// conversion functions, when no value is available
+func pathBuildError(segment string, dstt, srct reflect.Type, err error) error {
+	if e, ok := err.(*ConversionError); ok {
+		res := *e
+		res.Path = joinPath(segment, e.Path)
+		return &res
+	}
+	return &ConversionError{Path: segment, Src: srct, Dst: dstt, Reason: reasonOf(err), Err: err}
+}
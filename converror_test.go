@@ -0,0 +1,166 @@
+package typutil_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/KarpelesLab/typutil"
+)
+
+func TestConversionErrorPath(t *testing.T) {
+	type Item struct {
+		Name  string
+		Price int
+	}
+	type Order struct {
+		Customer *struct {
+			Age uint8
+		}
+		Items []*Item         `json:"items"`
+		Tags  map[string]int8 `json:"tags"`
+	}
+
+	tests := []struct {
+		name  string
+		src   map[string]any
+		path  string
+		value any
+		srct  reflect.Type
+		dstt  reflect.Type
+		is    error
+	}{
+		{
+			name: "slice of struct pointers",
+			src: map[string]any{"items": []any{
+				map[string]any{"Name": "a", "Price": 1},
+				map[string]any{"Name": "b", "Price": 2.5},
+			}},
+			path:  "items[1].Price",
+			value: 2.5,
+			srct:  reflect.TypeFor[float64](),
+			dstt:  reflect.TypeFor[int](),
+			is:    typutil.ErrPrecisionLoss,
+		},
+		{
+			name:  "pointer to struct",
+			src:   map[string]any{"Customer": map[string]any{"Age": 300}},
+			path:  "Customer.Age",
+			value: 300,
+			srct:  reflect.TypeFor[int](),
+			dstt:  reflect.TypeFor[uint8](),
+			is:    typutil.ErrOverflow,
+		},
+		{
+			name:  "map value",
+			src:   map[string]any{"tags": map[string]any{"x": 1, "y": "200"}},
+			path:  "tags[y]",
+			value: "200",
+			srct:  reflect.TypeFor[string](),
+			dstt:  reflect.TypeFor[int8](),
+			is:    typutil.ErrOverflow,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := typutil.As[Order](tt.src)
+			var cerr *typutil.ConversionError
+			if !errors.As(err, &cerr) {
+				t.Errorf("expected *ConversionError, got %v", err)
+				return
+			}
+			if cerr.Path != tt.path {
+				t.Errorf("unexpected path %q, expected %q", cerr.Path, tt.path)
+			}
+			if cerr.Value != tt.value {
+				t.Errorf("unexpected value %v, expected %v", cerr.Value, tt.value)
+			}
+			if cerr.Src != tt.srct || cerr.Dst != tt.dstt {
+				t.Errorf("unexpected types %s → %s", cerr.Src, cerr.Dst)
+			}
+			if !errors.Is(err, tt.is) {
+				t.Errorf("expected error to wrap %v, got %v", tt.is, err)
+			}
+			if err.Error()[:len(tt.path)] != tt.path {
+				t.Errorf("expected message to start with the path, got %q", err.Error())
+			}
+		})
+	}
+}
+
+func TestConversionErrorStructToStruct(t *testing.T) {
+	type Src struct {
+		Values []string
+	}
+	type Dst struct {
+		Values []int
+	}
+
+	_, err := typutil.As[Dst](Src{Values: []string{"1", "x"}})
+	var cerr *typutil.ConversionError
+	if !errors.As(err, &cerr) {
+		t.Errorf("expected *ConversionError, got %v", err)
+	} else if cerr.Path != "Values[1]" || cerr.Value != "x" {
+		t.Errorf("unexpected error path=%q value=%v", cerr.Path, cerr.Value)
+	}
+
+	var m map[string]int
+	err = typutil.Assign(&m, struct{ A, B string }{"1", "b"})
+	if !errors.As(err, &cerr) {
+		t.Errorf("expected *ConversionError, got %v", err)
+	} else if cerr.Path != "B" || cerr.Value != "b" {
+		t.Errorf("unexpected error path=%q value=%v", cerr.Path, cerr.Value)
+	}
+}
+
+func TestConversionErrorReason(t *testing.T) {
+	tests := []struct {
+		name   string
+		dst    any
+		src    any
+		reason typutil.ConversionReason
+		is     error
+	}{
+		{"overflow", new(int8), 300, typutil.ReasonOverflow, typutil.ErrOverflow},
+		{"precision", new(int), 1.5, typutil.ReasonPrecisionLoss, typutil.ErrPrecisionLoss},
+		{"syntax", new(int), "abc", typutil.ReasonSyntax, typutil.ErrSyntax},
+		{"time syntax", new(time.Time), "yesterday", typutil.ReasonSyntax, typutil.ErrSyntax},
+		{"base64 syntax", new([]byte), "!!", typutil.ReasonSyntax, typutil.ErrSyntax},
+		{"unsupported", new(int), struct{}{}, typutil.ReasonUnsupported, typutil.ErrAssignImpossible},
+		{"unsupported type", new(chan int), 1, typutil.ReasonUnsupported, typutil.ErrAssignImpossible},
+		{"nil pointer", new(int), (*int8)(nil), typutil.ReasonNilPointer, typutil.ErrNilPointerRead},
+		{"length", new([2]int), []int{1}, typutil.ReasonLength, typutil.ErrLengthMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := typutil.Assign(tt.dst, tt.src)
+			var cerr *typutil.ConversionError
+			if !errors.As(err, &cerr) {
+				t.Errorf("expected *ConversionError, got %v", err)
+				return
+			}
+			if cerr.Reason != tt.reason {
+				t.Errorf("unexpected reason %s, expected %s", cerr.Reason, tt.reason)
+			}
+			if !errors.Is(err, tt.is) {
+				t.Errorf("expected error to wrap %v, got %v", tt.is, err)
+			}
+			if cerr.Dst != reflect.TypeOf(tt.dst).Elem() {
+				t.Errorf("unexpected destination type %s", cerr.Dst)
+			}
+		})
+	}
+
+	// the reason of nested errors is preserved along with the path
+	type Obj struct {
+		Values []uint8
+	}
+	_, err := typutil.As[Obj](map[string]any{"Values": []any{1, "x"}})
+	var cerr *typutil.ConversionError
+	if !errors.As(err, &cerr) || cerr.Reason != typutil.ReasonSyntax || cerr.Path != "Values[1]" {
+		t.Errorf("unexpected error %#v", err)
+	}
+}
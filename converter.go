@@ -0,0 +1,270 @@
+package typutil
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// converterOption is a function that configures a Converter instance.
+//
+// Like funcOption for Callable, this follows the functional options pattern:
+// each option is a function that takes a *Converter and modifies it. Options
+// are applied once by NewConverter, before the converter is used.
+type converterOption func(*Converter)
+
+// Converter holds the settings and caches used to perform conversions.
+//
+// The package-level Assign, AssignReflect, As and Validate functions use a
+// default Converter. Creating a separate instance with NewConverter allows
+// different parts of a program to use different conversion policies without
+// affecting each other, as each Converter owns its own cache of conversion
+// functions and its own validator registry.
+//
+// A Converter is safe for concurrent use once created.
+type Converter struct {
+	converterConfig
+
+	parent   *Converter    // for variants, the converter they were derived from
+	cache    sync.Map      // map[assignConvType]assignFunc
+	cacheGen atomic.Uint64 // value of converterGeneration when cache was last cleared
+	variants sync.Map      // map[string]*Converter
+
+	validatorCache   map[reflect.Type]structValidator
+	validatorCacheLk sync.Mutex
+}
+
+// converterConfig holds the settings of a Converter, which are copied when
+// deriving a variant for a struct field
+type converterConfig struct {
+	tagNames        []string           // struct tags used for field names, "json" by default
+	namePolicy      NamePolicy         // names of fields without a name in their tag
+	caseSensitive   bool               // map keys must match field names exactly
+	disallowUnknown bool               // fail on map keys not matching any field
+	strictBool      bool               // only accept well known boolean strings
+	strictNum       bool               // only accept base 10 numeric strings
+	lossyNum        bool               // allow numbers to overflow or lose precision
+	timeLayouts     []string           // layouts used to parse and format time values
+	timeLoc         *time.Location     // time zone for time values, UTC if nil
+	unixMilli       bool               // unix timestamps are in milliseconds
+	durationUnit    time.Duration      // unit of numbers converted to durations, ns if zero
+	bytesEncoding   Encoding           // encoding of byte slices converted to or from strings
+	jsonFallback    bool               // retry failed conversions through JSON
+	collectErrors   bool               // keep going after an element fails
+	defaultOnNull   bool               // apply default tags to nil values too
+	merge           bool               // update existing values instead of replacing them
+	mergeAppend     bool               // append to existing slices when merging
+	validators      *validatorRegistry // validators specific to this converter
+	converters      *converterRegistry // conversion functions specific to this converter
+	polymorphs      *polyRegistry      // polymorphic interfaces specific to this converter
+}
+
+// defaultConverter is the Converter used by the package-level functions
+var defaultConverter = &Converter{converterConfig: converterConfig{tagNames: []string{"json"}, validators: globalValidators, converters: globalConverters, polymorphs: globalPolymorphs}}
+
+// NewConverter returns a new Converter configured with the given options.
+//
+// Without options, the returned Converter behaves like the package-level
+// functions. Validators registered globally with SetValidator remain visible to
+// the new Converter, while validators registered with Converter.SetValidator
+// are only visible to that instance.
+//
+// Example:
+//
+//	strict := typutil.NewConverter(typutil.StrictBool, typutil.StrictNumbers)
+//	var b bool
+//	err := strict.Assign(&b, "maybe") // fails, while typutil.Assign would set true
+func NewConverter(options ...converterOption) *Converter {
+	c := &Converter{
+		converterConfig: converterConfig{
+			tagNames:   []string{"json"},
+			validators: newValidatorRegistry(globalValidators),
+			converters: newConverterRegistry(globalConverters),
+			polymorphs: newPolyRegistry(globalPolymorphs),
+		},
+	}
+
+	for _, opt := range options {
+		opt(c)
+	}
+
+	return c
+}
+
+// TagName is a functional option for NewConverter that sets the name of the
+// struct tag used to match struct fields with map keys or other struct fields.
+// The default is "json".
+//
+// Whatever the tag name, a `typutil:"..."` tag takes precedence, which allows
+// renaming a field (`typutil:"name"`) or excluding it from conversions
+// (`typutil:"-"`) without changing how it is encoded to JSON.
+//
+// Example:
+//
+//	conv := NewConverter(TagName("form"))
+func TagName(name string) converterOption {
+	return TagNames(name)
+}
+
+// TagNames is a functional option for NewConverter that sets a list of struct
+// tags used to match struct fields, tried in order. The first tag giving a
+// name to a field, or excluding it with "-", is used. Tag options such as
+// "required" are read from all the listed tags.
+//
+// Example:
+//
+//	conv := NewConverter(TagNames("db", "json"))
+func TagNames(names ...string) converterOption {
+	return func(c *Converter) {
+		c.tagNames = slices.Clone(names)
+	}
+}
+
+// StrictBool is a functional option for NewConverter that restricts how values
+// are converted to bool. Strings must be one of "1", "t", "true", "y", "yes",
+// "on" or "0", "f", "false", "n", "no", "off", "" (case insensitive), numbers
+// are true when non-zero, and any other value causes an error.
+//
+// Without this option, AsBool is used, which for example converts "false" to
+// true as it is a non-empty string.
+func StrictBool(c *Converter) {
+	c.strictBool = true
+}
+
+// StrictNumbers is a functional option for NewConverter that restricts how
+// strings are converted to numbers. Strings must contain a base 10 number
+// (no 0x prefix or _ separators), and booleans are not accepted as numbers.
+func StrictNumbers(c *Converter) {
+	c.strictNum = true
+}
+
+// fieldConverter returns the converter to use for a struct field. This is c
+// itself unless the field has tags changing how its value is converted, such as
+// unit or encoding, in which case a variant of c is returned. When several fields are given
+// (destination first, then source) the first one having a given tag is used.
+func (c *Converter) fieldConverter(fields ...reflect.StructField) (*Converter, error) {
+	var key []string
+	var opts []converterOption
+
+	for _, f := range fields {
+		if tag, ok := f.Tag.Lookup("unit"); ok {
+			unit, ok := durationUnits[tag]
+			if !ok {
+				return nil, fmt.Errorf("invalid unit %q on field %s", tag, f.Name)
+			}
+			key = append(key, "unit="+tag)
+			opts = append(opts, DurationUnit(unit))
+			break
+		}
+	}
+	for _, f := range fields {
+		if tag, ok := f.Tag.Lookup("encoding"); ok {
+			enc, ok := encodingNames[tag]
+			if !ok {
+				return nil, fmt.Errorf("invalid encoding %q on field %s", tag, f.Name)
+			}
+			key = append(key, "encoding="+tag)
+			opts = append(opts, BytesEncoding(enc))
+			break
+		}
+	}
+
+	if len(key) == 0 {
+		return c, nil
+	}
+	return c.variant(strings.Join(key, ";"), opts...), nil
+}
+
+// variant returns a converter sharing c's settings, with opts applied. Variants
+// are cached by key and keep their own cache of conversion functions.
+func (c *Converter) variant(key string, opts ...converterOption) *Converter {
+	if v, ok := c.variants.Load(key); ok {
+		return v.(*Converter)
+	}
+
+	parent := c
+	if c.parent != nil {
+		parent = c.parent
+	}
+	v := &Converter{converterConfig: c.converterConfig, parent: parent}
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	res, _ := c.variants.LoadOrStore(key, v)
+	return res.(*Converter)
+}
+
+// typutilTag is the struct tag taking precedence over the converter's tags
+const typutilTag = "typutil"
+
+// fieldTags returns the values of the struct tags of f used by the converter,
+// in order of precedence
+func (c *Converter) fieldTags(f reflect.StructField) []string {
+	res := make([]string, 0, len(c.tagNames)+1)
+	for _, name := range append([]string{typutilTag}, c.tagNames...) {
+		if tag := f.Tag.Get(name); tag != "" {
+			res = append(res, tag)
+		}
+	}
+	return res
+}
+
+// fieldName returns the name used to match the given struct field, based on the
+// converter's tag names, whether that name comes from a tag, and false if the
+// field should be skipped.
+func (c *Converter) fieldName(f reflect.StructField) (name string, tagged bool, ok bool) {
+	for _, tag := range c.fieldTags(f) {
+		// check if tag renames field
+		if tag[0] == '-' {
+			return "", false, false
+		}
+		if tag[0] != ',' {
+			name, _, _ = strings.Cut(tag, ",")
+			return name, true, true
+		}
+	}
+
+	name = f.Name
+	if c.namePolicy != nil {
+		name = c.namePolicy(name)
+	}
+	return name, false, true
+}
+
+// asBool converts v to a bool according to the converter's bool policy
+func (c *Converter) asBool(v any) (bool, bool) {
+	if !c.strictBool {
+		return AsBool(v), true
+	}
+
+	v = BaseType(v)
+	switch r := v.(type) {
+	case bool:
+		return r, true
+	case string:
+		return parseStrictBool(r)
+	case []byte:
+		return parseStrictBool(string(r))
+	case nil:
+		return false, true
+	}
+	if n, ok := AsNumber(v); ok {
+		return AsBool(n), true
+	}
+	return false, false
+}
+
+func parseStrictBool(s string) (bool, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "1", "t", "true", "y", "yes", "on":
+		return true, true
+	case "0", "f", "false", "n", "no", "off", "":
+		return false, true
+	}
+	return false, false
+}
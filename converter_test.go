@@ -0,0 +1,138 @@
+package typutil_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/KarpelesLab/typutil"
+)
+
+func TestConverterTagName(t *testing.T) {
+	type Form struct {
+		Name string `json:"name" form:"user_name"`
+	}
+
+	conv := typutil.NewConverter(typutil.TagName("form"))
+
+	res, err := typutil.AsWith[Form](conv, map[string]any{"user_name": "alice", "name": "bob"})
+	if err != nil {
+		t.Errorf("AsWith failed: %s", err)
+	} else if res.Name != "alice" {
+		t.Errorf("expected alice (via form tag), got %q", res.Name)
+	}
+
+	// the default converter must not be affected
+	res, err = typutil.As[Form](map[string]any{"user_name": "alice", "name": "bob"})
+	if err != nil {
+		t.Errorf("As failed: %s", err)
+	} else if res.Name != "bob" {
+		t.Errorf("expected bob (via json tag), got %q", res.Name)
+	}
+}
+
+func TestConverterStrictBool(t *testing.T) {
+	conv := typutil.NewConverter(typutil.StrictBool)
+
+	tests := []struct {
+		input    any
+		expected bool
+	}{
+		{"true", true},
+		{"FALSE", false},
+		{"yes", true},
+		{"off", false},
+		{"", false},
+		{1, true},
+		{0, false},
+		{true, true},
+	}
+
+	for _, tt := range tests {
+		var b bool
+		if err := conv.Assign(&b, tt.input); err != nil {
+			t.Errorf("strict bool conversion of %v failed: %s", tt.input, err)
+		} else if b != tt.expected {
+			t.Errorf("strict bool conversion of %v: expected %v, got %v", tt.input, tt.expected, b)
+		}
+	}
+
+	var b bool
+	if err := conv.Assign(&b, "maybe"); err == nil {
+		t.Errorf("strict bool conversion of \"maybe\" should fail")
+	}
+
+	// default behavior is unchanged
+	if err := typutil.Assign(&b, "false"); err != nil || !b {
+		t.Errorf("default bool conversion of \"false\" expected true, got %v (err=%v)", b, err)
+	}
+}
+
+func TestConverterStrictNumbers(t *testing.T) {
+	conv := typutil.NewConverter(typutil.StrictNumbers)
+
+	i, err := typutil.AsWith[int](conv, "42")
+	if err != nil || i != 42 {
+		t.Errorf("expected 42, got %d (err=%v)", i, err)
+	}
+
+	for _, in := range []any{"0x10", "1_000", true} {
+		if _, err := typutil.AsWith[int](conv, in); err == nil {
+			t.Errorf("strict number conversion of %v should fail", in)
+		}
+		if _, err := typutil.AsWith[uint](conv, in); err == nil {
+			t.Errorf("strict unsigned conversion of %v should fail", in)
+		}
+	}
+
+	if _, err := typutil.AsWith[float64](conv, "0x1p-2"); err == nil {
+		t.Errorf("strict float conversion of hex float should fail")
+	}
+	f, err := typutil.AsWith[float64](conv, "1.5")
+	if err != nil || f != 1.5 {
+		t.Errorf("expected 1.5, got %v (err=%v)", f, err)
+	}
+
+	// default converter still accepts hex
+	i, err = typutil.As[int]("0x10")
+	if err != nil || i != 16 {
+		t.Errorf("expected 16, got %d (err=%v)", i, err)
+	}
+}
+
+func TestConverterValidators(t *testing.T) {
+	type Obj struct {
+		Code string `validator:"conv_test_upper"`
+	}
+	errNotUpper := errors.New("not upper case")
+
+	conv := typutil.NewConverter()
+	conv.SetValidator("conv_test_upper", func(s string) error {
+		for _, c := range s {
+			if c >= 'a' && c <= 'z' {
+				return errNotUpper
+			}
+		}
+		return nil
+	})
+
+	_, err := typutil.AsWith[Obj](conv, map[string]any{"Code": "abc"})
+	if !errors.Is(err, errNotUpper) {
+		t.Errorf("expected validator error, got %v", err)
+	}
+	if err := conv.Validate(&Obj{Code: "ABC"}); err != nil {
+		t.Errorf("unexpected validation error: %s", err)
+	}
+
+	// validator is only known to conv
+	if _, err := typutil.As[Obj](map[string]any{"Code": "abc"}); err != nil {
+		t.Errorf("default converter should not know conv_test_upper, got %s", err)
+	}
+
+	// global validators are still available
+	type Obj2 struct {
+		Name string `validator:"not_empty"`
+	}
+	if _, err := typutil.AsWith[Obj2](conv, map[string]any{"Name": ""}); !errors.Is(err, typutil.ErrEmptyValue) {
+		t.Errorf("expected ErrEmptyValue, got %v", err)
+	}
+}
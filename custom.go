@@ -0,0 +1,131 @@
+package typutil
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// converterRegistry holds conversion functions registered for pairs of types.
+// A registry with a parent falls back to it for pairs it does not define
+// itself.
+type converterRegistry struct {
+	parent *converterRegistry
+	m      map[assignConvType]assignFunc
+	lk     sync.RWMutex
+}
+
+// globalConverters is the registry used by RegisterConverter and RegisterConverterReflect
+var globalConverters = newConverterRegistry(nil)
+
+// converterGeneration changes each time a conversion function is registered,
+// so converters know the functions they cached may be outdated
+var converterGeneration atomic.Uint64
+
+func newConverterRegistry(parent *converterRegistry) *converterRegistry {
+	return &converterRegistry{parent: parent, m: make(map[assignConvType]assignFunc)}
+}
+
+func (r *converterRegistry) set(dstt, srct reflect.Type, f assignFunc) {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+
+	if f == nil {
+		delete(r.m, assignConvType{dstt, srct})
+	} else {
+		r.m[assignConvType{dstt, srct}] = f
+	}
+	converterGeneration.Add(1)
+}
+
+func (r *converterRegistry) get(dstt, srct reflect.Type) (assignFunc, bool) {
+	r.lk.RLock()
+	f, ok := r.m[assignConvType{dstt, srct}]
+	r.lk.RUnlock()
+
+	if !ok && r.parent != nil {
+		return r.parent.get(dstt, srct)
+	}
+	return f, ok
+}
+
+// RegisterConverter registers a function converting values of type Src to
+// type Dst, used by all converters.
+//
+// Registered functions are consulted before any built-in conversion, which
+// allows converting third-party types that do not implement any of the
+// interfaces used by Assign. Types must match exactly: a function registered
+// for uuid.UUID is also used for *uuid.UUID values, as pointers are resolved
+// first, but a function registered for an interface type is only used for
+// values whose static type is that interface. Passing a nil function removes a
+// previous registration.
+//
+// Example:
+//
+//	typutil.RegisterConverter(func(s string) (decimal.Decimal, error) {
+//		return decimal.NewFromString(s)
+//	})
+func RegisterConverter[Src, Dst any](fn func(Src) (Dst, error)) {
+	RegisterConverterWith(defaultConverter, fn)
+}
+
+// RegisterConverterWith registers a function converting values of type Src to
+// type Dst with the given Converter. When c is not the default converter, the
+// function is only used by c. See RegisterConverter for details.
+func RegisterConverterWith[Src, Dst any](c *Converter, fn func(Src) (Dst, error)) {
+	dstt := reflect.TypeFor[Dst]()
+	srct := reflect.TypeFor[Src]()
+
+	if fn == nil {
+		c.converters.set(dstt, srct, nil)
+		return
+	}
+	c.converters.set(dstt, srct, func(dst, src reflect.Value) error {
+		v, _ := src.Interface().(Src)
+		res, err := fn(v)
+		if err != nil {
+			return err
+		}
+		dst.Set(reflect.ValueOf(&res).Elem())
+		return nil
+	})
+}
+
+// RegisterConverterReflect registers a function converting values of type
+// srct to type dstt, used by all converters. It is the reflection-based
+// version of RegisterConverter: fn receives the destination and source values
+// and must set dst.
+func RegisterConverterReflect(dstt, srct reflect.Type, fn func(dst, src reflect.Value) error) {
+	defaultConverter.RegisterConverterReflect(dstt, srct, fn)
+}
+
+// RegisterConverterReflect registers a function converting values of type
+// srct to type dstt with this converter. See the package-level
+// RegisterConverterReflect for details.
+func (c *Converter) RegisterConverterReflect(dstt, srct reflect.Type, fn func(dst, src reflect.Value) error) {
+	if fn == nil {
+		c.converters.set(dstt, srct, nil)
+		return
+	}
+	c.converters.set(dstt, srct, fn)
+}
+
+// makeAssignCustom returns the registered function converting srct to dstt,
+// followed by the validation of dstt, or nil if there is none
+func (c *Converter) makeAssignCustom(dstt, srct reflect.Type) assignFunc {
+	fn, ok := c.converters.get(dstt, srct)
+	if !ok {
+		return nil
+	}
+	validator := c.getValidatorForType(dstt)
+	if len(validator) == 0 {
+		return fn
+	}
+
+	return func(dst, src reflect.Value) error {
+		if err := fn(dst, src); err != nil {
+			return err
+		}
+		return validator.validate(c, dst)
+	}
+}
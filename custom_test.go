@@ -0,0 +1,123 @@
+package typutil_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/KarpelesLab/typutil"
+)
+
+// decimal stands for a third-party type with unexported fields
+type decimal struct {
+	units int64
+	exp   int
+}
+
+func parseDecimal(s string) (decimal, error) {
+	whole, frac, _ := strings.Cut(s, ".")
+	n, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	return decimal{units: n, exp: len(frac)}, nil
+}
+
+func (d decimal) String() string {
+	s := strconv.FormatInt(d.units, 10)
+	if d.exp == 0 {
+		return s
+	}
+	return s[:len(s)-d.exp] + "." + s[len(s)-d.exp:]
+}
+
+// opaqueID stands for a third-party identifier type
+type opaqueID [4]byte
+
+func TestRegisterConverter(t *testing.T) {
+	conv := typutil.NewConverter()
+
+	// no conversion exists before registration
+	if _, err := typutil.AsWith[decimal](conv, "12.50"); err == nil {
+		t.Errorf("expected error before registration")
+	}
+
+	typutil.RegisterConverterWith(conv, parseDecimal)
+	d, err := typutil.AsWith[decimal](conv, "12.50")
+	if err != nil || d.units != 1250 || d.exp != 2 {
+		t.Errorf("string to decimal: got %+v (err=%v)", d, err)
+	}
+
+	// used for nested values and pointers too
+	type Invoice struct {
+		Total *decimal
+		Lines []decimal
+	}
+	inv, err := typutil.AsWith[Invoice](conv, map[string]any{"Total": "3.5", "Lines": []string{"1.5", "2"}})
+	if err != nil || inv.Total == nil || inv.Total.String() != "3.5" || len(inv.Lines) != 2 || inv.Lines[1].String() != "2" {
+		t.Errorf("unexpected value %+v (err=%v)", inv, err)
+	}
+
+	// errors are reported with their path
+	_, err = typutil.AsWith[Invoice](conv, map[string]any{"Lines": []string{"1", "x"}})
+	var cerr *typutil.ConversionError
+	if !errors.As(err, &cerr) || cerr.Path != "Lines[1]" {
+		t.Errorf("expected error on Lines[1], got %v", err)
+	}
+
+	// only known to conv
+	if _, err := typutil.As[decimal]("1"); err == nil {
+		t.Errorf("expected the default converter not to know about decimal")
+	}
+
+	// removing the registration invalidates cached functions
+	typutil.RegisterConverterWith[string, decimal](conv, nil)
+	if _, err := typutil.AsWith[decimal](conv, "12.50"); err == nil {
+		t.Errorf("expected error after removing the registration")
+	}
+}
+
+func TestRegisterConverterGlobal(t *testing.T) {
+	// registered converters take priority over built-in conversions, such as
+	// hex decoding of strings into byte arrays
+	id, err := typutil.As[opaqueID]("00000001")
+	if err != nil || id != (opaqueID{0, 0, 0, 1}) {
+		t.Errorf("unexpected value %v (err=%v)", id, err)
+	}
+
+	typutil.RegisterConverter(func(s string) (opaqueID, error) {
+		n, err := strconv.ParseUint(strings.TrimPrefix(s, "id-"), 10, 32)
+		if err != nil {
+			return opaqueID{}, err
+		}
+		return opaqueID{byte(n >> 24), byte(n >> 16), byte(n >> 8), byte(n)}, nil
+	})
+	defer typutil.RegisterConverter[string, opaqueID](nil)
+
+	id, err = typutil.As[opaqueID]("id-258")
+	if err != nil || id != (opaqueID{0, 0, 1, 2}) {
+		t.Errorf("unexpected value %v (err=%v)", id, err)
+	}
+
+	// visible to other converters as well
+	id, err = typutil.AsWith[opaqueID](typutil.NewConverter(), "id-1")
+	if err != nil || id != (opaqueID{0, 0, 0, 1}) {
+		t.Errorf("unexpected value %v (err=%v)", id, err)
+	}
+}
+
+func TestRegisterConverterReflect(t *testing.T) {
+	conv := typutil.NewConverter()
+	conv.RegisterConverterReflect(reflect.TypeFor[string](), reflect.TypeFor[decimal](), func(dst, src reflect.Value) error {
+		dst.SetString("dec:" + src.Interface().(decimal).String())
+		return nil
+	})
+
+	s, err := typutil.AsWith[string](conv, decimal{units: 125, exp: 1})
+	if err != nil || s != "dec:12.5" {
+		t.Errorf("decimal to string: got %q (err=%v)", s, err)
+	}
+}
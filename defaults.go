@@ -0,0 +1,99 @@
+package typutil
+
+import (
+	"reflect"
+)
+
+var stringType = reflect.TypeFor[string]()
+
+// DefaultOnNull is a functional option for NewConverter that makes the
+// default tag of a struct field also apply when the source map holds nil for
+// the field, instead of only when the key is missing.
+func DefaultOnNull(c *Converter) {
+	c.defaultOnNull = true
+}
+
+// defaultFunc sets a field to its default value
+type defaultFunc func(dst reflect.Value) error
+
+// makeFieldDefault returns the function setting the field f to its default
+// value when it is missing from a source map, or nil if there is none.
+//
+// The value of the default tag is converted to the field type like any other
+// string. Struct fields without a default tag get the defaults of their own
+// fields, recursively.
+func (c *Converter) makeFieldDefault(f reflect.StructField) (defaultFunc, error) {
+	tag, ok := f.Tag.Lookup("default")
+	if !ok {
+		if f.Type.Kind() == reflect.Struct {
+			return c.makeDefaults(f.Type)
+		}
+		return nil, nil
+	}
+
+	fc, err := c.fieldConverter(f)
+	if err != nil {
+		return nil, err
+	}
+	fnc, err := fc.getAssignFunc(f.Type, stringType)
+	if err != nil {
+		return nil, err
+	}
+	src := reflect.ValueOf(tag)
+
+	// report invalid defaults early
+	if err := fnc(reflect.New(f.Type).Elem(), src); err != nil {
+		return nil, err
+	}
+
+	return func(dst reflect.Value) error {
+		return fnc(dst, src)
+	}, nil
+}
+
+// makeDefaults returns the function setting the fields of the struct type t
+// to their default values, or nil if none of them has one
+func (c *Converter) makeDefaults(t reflect.Type) (defaultFunc, error) {
+	type fieldDefault struct {
+		index []int
+		set   defaultFunc
+	}
+	var defs []fieldDefault
+
+	for _, f := range c.structFields(t) {
+		def, err := c.makeFieldDefault(f.StructField)
+		if err != nil {
+			return nil, pathBuildError(f.name, f.Type, stringType, err)
+		}
+		if def != nil {
+			defs = append(defs, fieldDefault{index: f.Index, set: def})
+		}
+	}
+	if len(defs) == 0 {
+		return nil, nil
+	}
+
+	return func(dst reflect.Value) error {
+		for _, d := range defs {
+			dstf, err := fieldByIndexAlloc(dst, d.index)
+			if err != nil {
+				return err
+			}
+			if err := d.set(dstf); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, nil
+}
+
+// isNull returns true if v holds nil
+func isNull(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Interface, reflect.Pointer, reflect.Map, reflect.Slice:
+		return v.IsNil()
+	case reflect.Invalid:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package typutil_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/KarpelesLab/typutil"
+)
+
+func TestDefaultTag(t *testing.T) {
+	type TLS struct {
+		Enabled bool   `default:"true"`
+		MinVer  string `default:"1.2"`
+	}
+	type Server struct {
+		Host    string        `default:"localhost"`
+		Port    int           `json:"port" default:"8080"`
+		Timeout time.Duration `default:"30" unit:"s"`
+		Limit   *int          `default:"10"`
+		Debug   bool
+		TLS     TLS
+		Proxy   *TLS
+	}
+
+	s, err := typutil.As[Server](map[string]any{"Host": "example.com"})
+	if err != nil {
+		t.Errorf("map to struct failed: %s", err)
+		return
+	}
+	if s.Host != "example.com" {
+		t.Errorf("present key should not be defaulted, got %q", s.Host)
+	}
+	if s.Port != 8080 || s.Timeout != 30*time.Second {
+		t.Errorf("unexpected defaults %d %s", s.Port, s.Timeout)
+	}
+	if s.Limit == nil || *s.Limit != 10 {
+		t.Errorf("expected pointer default to be allocated, got %v", s.Limit)
+	}
+	if !s.TLS.Enabled || s.TLS.MinVer != "1.2" {
+		t.Errorf("expected nested defaults, got %+v", s.TLS)
+	}
+	if s.Proxy != nil {
+		t.Errorf("pointer to struct without default should stay nil, got %+v", s.Proxy)
+	}
+
+	// the defaults of nested structs also apply when the nested map lacks keys
+	s, err = typutil.As[Server](map[string]any{"TLS": map[string]any{"MinVer": "1.3"}, "Proxy": map[string]any{}})
+	if err != nil || !s.TLS.Enabled || s.TLS.MinVer != "1.3" || s.Proxy == nil || !s.Proxy.Enabled {
+		t.Errorf("unexpected value %+v %+v (err=%v)", s.TLS, s.Proxy, err)
+	}
+
+	// present zero values and null are kept by default
+	s, err = typutil.As[Server](map[string]any{"port": 0})
+	if err != nil || s.Port != 0 {
+		t.Errorf("expected zero value to be kept, got %d (err=%v)", s.Port, err)
+	}
+
+	conv := typutil.NewConverter(typutil.DefaultOnNull)
+	s, err = typutil.AsWith[Server](conv, map[string]any{"port": nil, "Limit": nil})
+	if err != nil || s.Port != 8080 || s.Limit == nil || *s.Limit != 10 {
+		t.Errorf("expected null to use defaults, got %+v (err=%v)", s, err)
+	}
+
+	// invalid defaults are reported when building the conversion
+	type Bad struct {
+		N int `default:"abc"`
+	}
+	_, err = typutil.As[Bad](map[string]any{})
+	var cerr *typutil.ConversionError
+	if !errors.As(err, &cerr) || cerr.Path != "N" || !errors.Is(err, typutil.ErrSyntax) {
+		t.Errorf("expected syntax error on N, got %v", err)
+	}
+}
@@ -0,0 +1,257 @@
+package typutil
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeFor[time.Duration]()
+
+// durationUnits lists the values accepted in the unit struct tag
+var durationUnits = map[string]time.Duration{
+	"ns": time.Nanosecond,
+	"us": time.Microsecond,
+	"µs": time.Microsecond,
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+	"d":  24 * time.Hour,
+}
+
+// DurationUnit is a functional option for NewConverter that sets the unit of
+// bare numbers converted to or from time.Duration values. The default is
+// nanoseconds, the unit of time.Duration itself.
+//
+// The unit can also be set for a single struct field with the unit tag, which
+// accepts ns, us, ms, s, m, h and d:
+//
+//	type Config struct {
+//	    Timeout time.Duration `unit:"s"` // 30 → 30 seconds, "1m" → 1 minute
+//	}
+func DurationUnit(unit time.Duration) converterOption {
+	return func(c *Converter) {
+		c.durationUnit = unit
+	}
+}
+
+func (c *Converter) getDurationUnit() time.Duration {
+	if c.durationUnit <= 0 {
+		return time.Nanosecond
+	}
+	return c.durationUnit
+}
+
+// parseDuration parses s as a Go duration ("1h30m"), an ISO 8601 duration ("PT90M")
+// or a bare number expressed in the converter's duration unit.
+func (c *Converter) parseDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if d, err := time.ParseDuration(s); err == nil {
+		return d, nil
+	}
+	if strings.HasPrefix(strings.TrimLeft(s, "+-"), "P") {
+		return parseISODuration(s)
+	}
+	if n, ok := c.asNumber(s); ok {
+		return c.numberDuration(n)
+	}
+	return 0, fmt.Errorf("%w: failed to parse %q as duration", ErrSyntax, s)
+}
+
+// numberDuration returns the duration for n (an int64, uint64 or float64) in the converter's unit
+func (c *Converter) numberDuration(n any) (time.Duration, error) {
+	unit := c.getDurationUnit()
+
+	switch x := n.(type) {
+	case int64:
+		if x > math.MaxInt64/int64(unit) || x < math.MinInt64/int64(unit) {
+			return 0, fmt.Errorf("%w: %d%s does not fit in a duration", ErrOverflow, x, unitName(unit))
+		}
+		return time.Duration(x) * unit, nil
+	case uint64:
+		if x > math.MaxInt64 {
+			return 0, fmt.Errorf("%w: %d does not fit in a duration", ErrOverflow, x)
+		}
+		return c.numberDuration(int64(x))
+	case float64:
+		f := x * float64(unit)
+		r := math.Round(f)
+		if math.IsNaN(f) || r < -0x1p63 || r >= 0x1p63 {
+			return 0, fmt.Errorf("%w: %v%s does not fit in a duration", ErrOverflow, x, unitName(unit))
+		}
+		if r != f && !c.lossyNum {
+			return 0, fmt.Errorf("%w: %v%s is not a whole number of nanoseconds", ErrPrecisionLoss, x, unitName(unit))
+		}
+		return time.Duration(r), nil
+	}
+	return 0, fmt.Errorf("%w: cannot convert %T to duration", ErrAssignImpossible, n)
+}
+
+func unitName(unit time.Duration) string {
+	for k, v := range durationUnits {
+		if v == unit && k != "µs" {
+			return k
+		}
+	}
+	return "×" + unit.String()
+}
+
+// parseISODuration parses an ISO 8601 duration such as "P1DT2H30M" or "PT0.5S".
+// Years and months are rejected as their length depends on the calendar.
+func parseISODuration(s string) (time.Duration, error) {
+	orig := s
+	neg := false
+	switch {
+	case strings.HasPrefix(s, "-"):
+		neg = true
+		s = s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+	if len(s) < 2 || s[0] != 'P' {
+		return 0, fmt.Errorf("%w: invalid ISO 8601 duration %q", ErrSyntax, orig)
+	}
+	s = s[1:]
+
+	var d time.Duration
+	inTime := false
+	for len(s) > 0 {
+		if s[0] == 'T' {
+			if inTime || len(s) == 1 {
+				return 0, fmt.Errorf("%w: invalid ISO 8601 duration %q", ErrSyntax, orig)
+			}
+			inTime = true
+			s = s[1:]
+			continue
+		}
+
+		i := 0
+		for i < len(s) && (s[i] >= '0' && s[i] <= '9' || s[i] == '.' || s[i] == ',') {
+			i++
+		}
+		if i == 0 || i == len(s) {
+			return 0, fmt.Errorf("%w: invalid ISO 8601 duration %q", ErrSyntax, orig)
+		}
+		num, designator := strings.Replace(s[:i], ",", ".", 1), s[i]
+		s = s[i+1:]
+
+		var unit time.Duration
+		switch {
+		case !inTime && designator == 'W':
+			unit = 7 * 24 * time.Hour
+		case !inTime && designator == 'D':
+			unit = 24 * time.Hour
+		case inTime && designator == 'H':
+			unit = time.Hour
+		case inTime && designator == 'M':
+			unit = time.Minute
+		case inTime && designator == 'S':
+			unit = time.Second
+		case !inTime && (designator == 'Y' || designator == 'M'):
+			return 0, fmt.Errorf("%w: unsupported ISO 8601 duration %q: years and months have no fixed length", ErrAssignImpossible, orig)
+		default:
+			return 0, fmt.Errorf("%w: invalid ISO 8601 duration %q", ErrSyntax, orig)
+		}
+
+		whole, frac, _ := strings.Cut(num, ".")
+		w, err := strconv.ParseInt(whole, 10, 64)
+		if err != nil || w > (math.MaxInt64-int64(d))/int64(unit) {
+			return 0, fmt.Errorf("%w: invalid ISO 8601 duration %q", ErrOverflow, orig)
+		}
+		d += time.Duration(w) * unit
+		if frac != "" {
+			f, err := strconv.ParseFloat("0."+frac, 64)
+			if err != nil {
+				return 0, fmt.Errorf("%w: invalid ISO 8601 duration %q", ErrSyntax, orig)
+			}
+			d += time.Duration(math.Round(f * float64(unit)))
+		}
+	}
+
+	if neg {
+		d = -d
+	}
+	return d, nil
+}
+
+func (c *Converter) makeAssignToDuration(dstt, srct reflect.Type) (assignFunc, error) {
+	switch srct.Kind() {
+	case reflect.String:
+		f := func(dst, src reflect.Value) error {
+			d, err := c.parseDuration(src.String())
+			if err != nil {
+				return err
+			}
+			dst.SetInt(int64(d))
+			return nil
+		}
+		return f, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		f := func(dst, src reflect.Value) error {
+			n, _ := c.numberOf(src)
+			d, err := c.numberDuration(n)
+			if err != nil {
+				return err
+			}
+			dst.SetInt(int64(d))
+			return nil
+		}
+		return f, nil
+	case reflect.Interface:
+		// perform this runtime
+		return c.makeAssignAnyToRuntime(dstt, srct), nil
+	}
+	// fallback to regular integer conversion
+	return c.makeAssignToInt(dstt, srct), nil
+}
+
+// makeAssignFromDuration returns a function converting a time.Duration into dstt, or
+// nil if the generic conversion for dstt's kind should be used.
+func (c *Converter) makeAssignFromDuration(dstt, srct reflect.Type) assignFunc {
+	switch dstt.Kind() {
+	case reflect.String:
+		return func(dst, src reflect.Value) error {
+			dst.SetString(time.Duration(src.Int()).String())
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if c.durationUnit <= 0 {
+			return nil
+		}
+		unit := c.durationUnit
+		return func(dst, src reflect.Value) error {
+			d := src.Int()
+			if d%int64(unit) != 0 && !c.lossyNum {
+				return fmt.Errorf("%w: %s is not a whole number of %s", ErrPrecisionLoss, time.Duration(d), unitName(unit))
+			}
+			return c.setInt(dst, d/int64(unit))
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if c.durationUnit <= 0 {
+			return nil
+		}
+		unit := c.durationUnit
+		return func(dst, src reflect.Value) error {
+			d := src.Int()
+			if d%int64(unit) != 0 && !c.lossyNum {
+				return fmt.Errorf("%w: %s is not a whole number of %s", ErrPrecisionLoss, time.Duration(d), unitName(unit))
+			}
+			return c.setUint(dst, d/int64(unit))
+		}
+	case reflect.Float32, reflect.Float64:
+		if c.durationUnit <= 0 {
+			return nil
+		}
+		unit := c.durationUnit
+		return func(dst, src reflect.Value) error {
+			return c.setFloat(dst, float64(src.Int())/float64(unit))
+		}
+	}
+	return nil
+}
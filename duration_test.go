@@ -0,0 +1,124 @@
+package typutil_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/KarpelesLab/typutil"
+)
+
+func TestAsDuration(t *testing.T) {
+	tests := []struct {
+		input    any
+		expected time.Duration
+	}{
+		{"1h30m", 90 * time.Minute},
+		{"-5s", -5 * time.Second},
+		{"PT90M", 90 * time.Minute},
+		{"P1DT2H", 26 * time.Hour},
+		{"P2W", 14 * 24 * time.Hour},
+		{"PT0.5S", 500 * time.Millisecond},
+		{"PT1,5M", 90 * time.Second},
+		{"-PT1H", -time.Hour},
+		{"0", 0},
+		{"1500", 1500}, // nanoseconds by default
+		{int64(1500), 1500},
+		{2.0, 2},
+	}
+
+	for _, tt := range tests {
+		d, err := typutil.As[time.Duration](tt.input)
+		if err != nil {
+			t.Errorf("As[time.Duration](%v) failed: %s", tt.input, err)
+		} else if d != tt.expected {
+			t.Errorf("As[time.Duration](%v) = %s, expected %s", tt.input, d, tt.expected)
+		}
+	}
+
+	for _, in := range []string{"P", "PT", "P1Y", "P1M", "PT1D", "P1H", "1 hour", "PT1.5"} {
+		if _, err := typutil.As[time.Duration](in); err == nil {
+			t.Errorf("As[time.Duration](%q) should fail", in)
+		}
+	}
+}
+
+func TestDurationUnit(t *testing.T) {
+	type Config struct {
+		Timeout  time.Duration `unit:"s"`
+		Delay    time.Duration `unit:"ms"`
+		Interval time.Duration
+		Retries  []time.Duration `unit:"m"`
+		Sub      struct {
+			Wait time.Duration
+		}
+	}
+
+	c, err := typutil.As[Config](map[string]any{
+		"Timeout":  30,
+		"Delay":    "250",
+		"Interval": "1m",
+		"Retries":  []any{1, "2", "PT3M"},
+		"Sub":      map[string]any{"Wait": 10},
+	})
+	if err != nil {
+		t.Errorf("map to struct failed: %s", err)
+		return
+	}
+	if c.Timeout != 30*time.Second {
+		t.Errorf("unexpected Timeout %s", c.Timeout)
+	}
+	if c.Delay != 250*time.Millisecond {
+		t.Errorf("unexpected Delay %s", c.Delay)
+	}
+	if c.Interval != time.Minute {
+		t.Errorf("unexpected Interval %s", c.Interval)
+	}
+	if len(c.Retries) != 3 || c.Retries[0] != time.Minute || c.Retries[1] != 2*time.Minute || c.Retries[2] != 3*time.Minute {
+		t.Errorf("unexpected Retries %v", c.Retries)
+	}
+	// the unit tag does not leak into nested structs
+	if c.Sub.Wait != 10 {
+		t.Errorf("unexpected Sub.Wait %s", c.Sub.Wait)
+	}
+
+	// fractional seconds are fine as long as they are whole nanoseconds
+	c, err = typutil.As[Config](map[string]any{"Timeout": 1.5})
+	if err != nil || c.Timeout != 1500*time.Millisecond {
+		t.Errorf("unexpected Timeout %s (err=%v)", c.Timeout, err)
+	}
+
+	conv := typutil.NewConverter(typutil.DurationUnit(time.Second))
+	d, err := typutil.AsWith[time.Duration](conv, 5)
+	if err != nil || d != 5*time.Second {
+		t.Errorf("unexpected duration %s (err=%v)", d, err)
+	}
+}
+
+func TestDurationToValues(t *testing.T) {
+	s, err := typutil.As[string](90 * time.Minute)
+	if err != nil || s != "1h30m0s" {
+		t.Errorf("duration to string: got %q (err=%v)", s, err)
+	}
+
+	i, err := typutil.As[int64](time.Second)
+	if err != nil || i != int64(time.Second) {
+		t.Errorf("duration to int64: got %d (err=%v)", i, err)
+	}
+
+	type Src struct {
+		Timeout time.Duration
+	}
+	type Dst struct {
+		Timeout int `unit:"s"`
+	}
+	dst, err := typutil.As[Dst](Src{Timeout: 2 * time.Minute})
+	if err != nil || dst.Timeout != 120 {
+		t.Errorf("duration to int seconds: got %d (err=%v)", dst.Timeout, err)
+	}
+
+	_, err = typutil.As[Dst](Src{Timeout: 1500 * time.Millisecond})
+	if !errors.Is(err, typutil.ErrPrecisionLoss) {
+		t.Errorf("expected ErrPrecisionLoss, got %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package typutil_test
+
+import (
+	"testing"
+
+	"github.com/KarpelesLab/typutil"
+)
+
+type BaseModel struct {
+	ID      string
+	Created int64 `json:"created"`
+}
+
+type Timestamps struct {
+	Updated int64
+	Name    string // ambiguous with Audit.Name, both at depth 1
+}
+
+type Audit struct {
+	Name string
+}
+
+type hidden struct {
+	Secret string
+}
+
+type Article struct {
+	BaseModel
+	*Timestamps
+	Audit
+	hidden
+	Title string
+	ID    int // shadows BaseModel.ID
+}
+
+func TestEmbeddedMapToStruct(t *testing.T) {
+	a, err := typutil.As[Article](map[string]any{
+		"ID":      "42",
+		"created": 1700000000,
+		"Updated": "1700000001",
+		"Title":   "hello",
+		"Name":    "ignored",
+		"Secret":  "s3cr3t",
+	})
+	if err != nil {
+		t.Errorf("map to struct failed: %s", err)
+		return
+	}
+	if a.ID != 42 || a.BaseModel.ID != "" {
+		t.Errorf("expected outer ID to shadow BaseModel.ID, got %d/%q", a.ID, a.BaseModel.ID)
+	}
+	if a.Created != 1700000000 {
+		t.Errorf("unexpected Created %d", a.Created)
+	}
+	if a.Timestamps == nil || a.Updated != 1700000001 {
+		t.Errorf("expected embedded pointer to be allocated, got %+v", a.Timestamps)
+	}
+	if a.Audit.Name != "" || a.Timestamps.Name != "" {
+		t.Errorf("ambiguous Name should be ignored, got %q/%q", a.Audit.Name, a.Timestamps.Name)
+	}
+	if a.Secret != "s3cr3t" {
+		t.Errorf("expected field of unexported embedded struct to be set, got %q", a.Secret)
+	}
+
+	// embedded pointers are only allocated when one of their fields is set
+	a, err = typutil.As[Article](map[string]any{"Title": "x"})
+	if err != nil || a.Timestamps != nil {
+		t.Errorf("unexpected Timestamps %+v (err=%v)", a.Timestamps, err)
+	}
+}
+
+func TestEmbeddedStructToStruct(t *testing.T) {
+	type Flat struct {
+		ID      string
+		Created string `json:"created"`
+		Updated int64
+		Title   string
+	}
+
+	a, err := typutil.As[Article](Flat{ID: "7", Created: "100", Updated: 200, Title: "t"})
+	if err != nil {
+		t.Errorf("flat to embedded failed: %s", err)
+		return
+	}
+	if a.ID != 7 || a.Created != 100 || a.Timestamps == nil || a.Updated != 200 || a.Title != "t" {
+		t.Errorf("unexpected value %+v", a)
+	}
+
+	f, err := typutil.As[Flat](Article{BaseModel: BaseModel{ID: "x", Created: 5}, ID: 9, Title: "t"})
+	if err != nil {
+		t.Errorf("embedded to flat failed: %s", err)
+		return
+	}
+	// nil embedded pointers are skipped in the source
+	if f.ID != "9" || f.Created != "5" || f.Updated != 0 || f.Title != "t" {
+		t.Errorf("unexpected value %+v", f)
+	}
+}
+
+func TestEmbeddedStructToMap(t *testing.T) {
+	var m map[string]any
+	err := typutil.Assign(&m, Article{
+		BaseModel:  BaseModel{ID: "x", Created: 5},
+		Timestamps: &Timestamps{Updated: 6},
+		ID:         9,
+	})
+	if err != nil {
+		t.Errorf("struct to map failed: %s", err)
+		return
+	}
+	if m["ID"] != 9 || m["created"] != int64(5) || m["Updated"] != int64(6) || m["Secret"] != "" {
+		t.Errorf("unexpected map %v", m)
+	}
+	if _, ok := m["Name"]; ok {
+		t.Errorf("ambiguous Name should not be in map")
+	}
+	if _, ok := m["BaseModel"]; ok {
+		t.Errorf("embedded struct should not appear as a field")
+	}
+}
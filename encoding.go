@@ -0,0 +1,149 @@
+package typutil
+
+import (
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// Encoding selects how byte slices and arrays are converted to and from
+// strings.
+type Encoding int
+
+const (
+	EncodingBase64       Encoding = iota // standard base64 with padding (default)
+	EncodingBase64URL                    // URL-safe base64 with padding
+	EncodingBase64Raw                    // standard base64 without padding
+	EncodingBase64RawURL                 // URL-safe base64 without padding
+	EncodingHex                          // lowercase hexadecimal
+	EncodingBase32                       // standard base32 with padding
+	EncodingRaw                          // the bytes of the string, unencoded
+	EncodingAuto                         // detect the encoding when decoding, base64 when encoding
+)
+
+// encodingNames lists the values accepted in the encoding struct tag
+var encodingNames = map[string]Encoding{
+	"base64":       EncodingBase64,
+	"base64url":    EncodingBase64URL,
+	"base64raw":    EncodingBase64Raw,
+	"base64rawurl": EncodingBase64RawURL,
+	"hex":          EncodingHex,
+	"base32":       EncodingBase32,
+	"raw":          EncodingRaw,
+	"auto":         EncodingAuto,
+}
+
+// autoEncodings are the encodings tried in order by EncodingAuto
+var autoEncodings = []Encoding{EncodingHex, EncodingBase64, EncodingBase64URL, EncodingBase64Raw, EncodingBase64RawURL, EncodingBase32}
+
+// BytesEncoding is a functional option for NewConverter that sets the encoding
+// of strings converted to or from byte slices and arrays. The default is
+// standard base64, except that strings of exactly twice as many hex digits as
+// a byte array has elements are decoded as hex.
+//
+// The encoding can also be set for a single struct field with the encoding
+// tag, which accepts base64, base64url, base64raw, base64rawurl, hex, base32,
+// raw and auto:
+//
+//	type File struct {
+//	    SHA256 []byte `encoding:"hex"`
+//	    Token  []byte `encoding:"base64rawurl"`
+//	}
+//
+// With EncodingAuto, strings are decoded as hex if possible, then as base64
+// (standard then URL-safe, padded then unpadded), and finally as base32. For
+// byte arrays, encodings that do not give the length of the array are skipped.
+func BytesEncoding(enc Encoding) converterOption {
+	return func(c *Converter) {
+		c.bytesEncoding = enc
+	}
+}
+
+// encode returns the string representation of b
+func (e Encoding) encode(b []byte) string {
+	switch e {
+	case EncodingBase64URL:
+		return base64.URLEncoding.EncodeToString(b)
+	case EncodingBase64Raw:
+		return base64.RawStdEncoding.EncodeToString(b)
+	case EncodingBase64RawURL:
+		return base64.RawURLEncoding.EncodeToString(b)
+	case EncodingHex:
+		return hex.EncodeToString(b)
+	case EncodingBase32:
+		return base32.StdEncoding.EncodeToString(b)
+	case EncodingRaw:
+		return string(b)
+	default:
+		return base64.StdEncoding.EncodeToString(b)
+	}
+}
+
+// decode returns the bytes represented by s
+func (e Encoding) decode(s string) ([]byte, error) {
+	var (
+		dec []byte
+		err error
+	)
+	switch e {
+	case EncodingBase64URL:
+		dec, err = base64.URLEncoding.DecodeString(s)
+	case EncodingBase64Raw:
+		dec, err = base64.RawStdEncoding.DecodeString(s)
+	case EncodingBase64RawURL:
+		dec, err = base64.RawURLEncoding.DecodeString(s)
+	case EncodingHex:
+		dec, err = hex.DecodeString(s)
+	case EncodingBase32:
+		dec, err = base32.StdEncoding.DecodeString(s)
+	case EncodingRaw:
+		return []byte(s), nil
+	case EncodingAuto:
+		for _, enc := range autoEncodings {
+			if dec, err := enc.decode(s); err == nil {
+				return dec, nil
+			}
+		}
+		return nil, fmt.Errorf("%w: unknown encoding for %q", ErrSyntax, s)
+	default:
+		dec, err = base64.StdEncoding.DecodeString(s)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSyntax, err)
+	}
+	return dec, nil
+}
+
+// decodeFixed decodes s as exactly n bytes. With the default base64 encoding,
+// strings made of 2*n hex digits are decoded as hex, and EncodingAuto only
+// accepts encodings producing n bytes.
+func (e Encoding) decodeFixed(s string, n int) ([]byte, error) {
+	if e == EncodingBase64 && len(s) == n*2 {
+		if dec, err := hex.DecodeString(s); err == nil {
+			return dec, nil
+		}
+	}
+	if e == EncodingAuto {
+		decoded := false
+		for _, enc := range autoEncodings {
+			if dec, err := enc.decode(s); err == nil {
+				if len(dec) == n {
+					return dec, nil
+				}
+				decoded = true
+			}
+		}
+		if decoded {
+			return nil, fmt.Errorf("%w: no encoding of %q gives %d bytes", ErrLengthMismatch, s, n)
+		}
+	}
+	dec, err := e.decode(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(dec) != n {
+		return nil, fmt.Errorf("%w: expected %d bytes, got %d", ErrLengthMismatch, n, len(dec))
+	}
+	return dec, nil
+}
@@ -0,0 +1,106 @@
+package typutil_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/KarpelesLab/typutil"
+)
+
+func TestBytesEncodingTag(t *testing.T) {
+	type File struct {
+		SHA1   [4]byte `encoding:"hex"`
+		Digest []byte  `encoding:"hex"`
+		Token  []byte  `encoding:"base64rawurl"`
+		Key    []byte  `encoding:"base32"`
+		Raw    []byte  `encoding:"raw"`
+		Any    []byte  `encoding:"auto"`
+		Data   []byte
+	}
+
+	data := []byte{0xfb, 0xff, 0x01}
+	f, err := typutil.As[File](map[string]any{
+		"SHA1":   "deadbeef",
+		"Digest": "fbff01",
+		"Token":  "-_8B",
+		"Key":    "7P7QC===",
+		"Raw":    "hello",
+		"Any":    "+/8B",
+		"Data":   "+/8B",
+	})
+	if err != nil {
+		t.Errorf("map to struct failed: %s", err)
+		return
+	}
+	if f.SHA1 != [4]byte{0xde, 0xad, 0xbe, 0xef} {
+		t.Errorf("unexpected SHA1 %x", f.SHA1)
+	}
+	for name, v := range map[string][]byte{"Digest": f.Digest, "Token": f.Token, "Key": f.Key, "Any": f.Any, "Data": f.Data} {
+		if !bytes.Equal(v, data) {
+			t.Errorf("unexpected %s %x", name, v)
+		}
+	}
+	if string(f.Raw) != "hello" {
+		t.Errorf("unexpected Raw %q", f.Raw)
+	}
+
+	// values round-trip with the same encoding
+	m, err := typutil.As[map[string]string](f)
+	if err != nil {
+		t.Errorf("struct to map failed: %s", err)
+		return
+	}
+	expected := map[string]string{"SHA1": "deadbeef", "Digest": "fbff01", "Token": "-_8B", "Key": "7P7QC===", "Raw": "hello", "Any": "+/8B", "Data": "+/8B"}
+	for k, v := range expected {
+		if m[k] != v {
+			t.Errorf("unexpected %s %q, expected %q", k, m[k], v)
+		}
+	}
+
+	if _, err := typutil.As[File](map[string]any{"Digest": "xyz"}); !errors.Is(err, typutil.ErrSyntax) {
+		t.Errorf("expected ErrSyntax, got %v", err)
+	}
+
+	type Bad struct {
+		Data []byte `encoding:"rot13"`
+	}
+	if _, err := typutil.As[Bad](map[string]any{"Data": "x"}); err == nil {
+		t.Errorf("expected error for invalid encoding")
+	}
+}
+
+func TestBytesEncodingOption(t *testing.T) {
+	conv := typutil.NewConverter(typutil.BytesEncoding(typutil.EncodingHex))
+
+	b, err := typutil.AsWith[[]byte](conv, "0a0b")
+	if err != nil || !bytes.Equal(b, []byte{10, 11}) {
+		t.Errorf("unexpected value %x (err=%v)", b, err)
+	}
+	s, err := typutil.AsWith[string](conv, []byte{10, 11})
+	if err != nil || s != "0a0b" {
+		t.Errorf("unexpected value %q (err=%v)", s, err)
+	}
+
+	// auto-detection
+	conv = typutil.NewConverter(typutil.BytesEncoding(typutil.EncodingAuto))
+	for _, in := range []string{"fbff01", "+/8B", "-_8B", "7P7QC==="} {
+		b, err := typutil.AsWith[[]byte](conv, in)
+		if err != nil || !bytes.Equal(b, []byte{0xfb, 0xff, 0x01}) {
+			t.Errorf("auto decode of %q: got %x (err=%v)", in, b, err)
+		}
+	}
+	if _, err := typutil.AsWith[[]byte](conv, "not encoded!"); !errors.Is(err, typutil.ErrSyntax) {
+		t.Errorf("expected ErrSyntax, got %v", err)
+	}
+
+	// byte arrays only accept encodings giving the right length, "AAAA" is
+	// valid hex but only decodes to 3 bytes as base64
+	a, err := typutil.AsWith[[3]byte](conv, "AAAA")
+	if err != nil || a != [3]byte{} {
+		t.Errorf("unexpected value %x (err=%v)", a, err)
+	}
+	if _, err := typutil.AsWith[[4]byte](conv, "AAAA"); !errors.Is(err, typutil.ErrLengthMismatch) {
+		t.Errorf("expected ErrLengthMismatch, got %v", err)
+	}
+}
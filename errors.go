@@ -12,6 +12,17 @@ var (
 	ErrNilPointerRead            = errors.New("attempt to read from a nil pointer")
 	ErrDestinationNotAddressable = errors.New("assign: destination cannot be addressed")
 	ErrInvalidSource             = errors.New("assign source is not valid")
+	ErrOverflow                  = errors.New("assign: value out of range for destination type")
+	ErrPrecisionLoss             = errors.New("assign: value cannot be represented exactly in destination type")
+	ErrLengthMismatch            = errors.New("assign: source length does not match destination array length")
+	ErrUnknownField              = errors.New("assign: unknown field")
+	ErrSyntax                    = errors.New("assign: invalid syntax")
+	ErrMissingField              = errors.New("assign: required field is missing")
+
+	// Patch-related errors
+	ErrPatchPath       = errors.New("patch: path does not exist")
+	ErrPatchOperation  = errors.New("patch: invalid operation")
+	ErrPatchTestFailed = errors.New("patch: test operation failed")
 
 	// Validation-related errors
 	ErrEmptyValue        = errors.New("validator: value must not be empty")
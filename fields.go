@@ -0,0 +1,176 @@
+package typutil
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// fieldInfo describes a struct field matched by name during conversions.
+// Index holds the full index sequence of the field, which is longer than one
+// for fields promoted from embedded structs.
+type fieldInfo struct {
+	reflect.StructField
+	name     string // name used to match the field
+	tagged   bool   // name comes from a struct tag
+	required bool   // the field must be present in source maps
+}
+
+// structFields returns the fields of the struct type t that take part in
+// conversions, including the fields of embedded structs, following the rules
+// of encoding/json:
+//
+//   - fields of embedded structs (or pointers to structs) without a name in
+//     their tag are promoted, as long as the embedded type is a struct
+//   - when several fields share a name, the least nested one wins
+//   - among fields at the same depth, a field named by a tag wins
+//   - if this still leaves more than one field, they are all ignored
+func (c *Converter) structFields(t reflect.Type) []*fieldInfo {
+	type candidate struct {
+		typ   reflect.Type
+		index []int
+	}
+
+	var fields []*fieldInfo
+	next := []candidate{{typ: t}}
+	var count, nextCount map[reflect.Type]int
+	visited := make(map[reflect.Type]bool)
+
+	for len(next) > 0 {
+		current := next
+		next = nil
+		count, nextCount = nextCount, make(map[reflect.Type]int)
+
+		for _, cand := range current {
+			if visited[cand.typ] {
+				continue
+			}
+			visited[cand.typ] = true
+
+			for i := 0; i < cand.typ.NumField(); i++ {
+				sf := cand.typ.Field(i)
+				if sf.Anonymous {
+					ft := sf.Type
+					if ft.Kind() == reflect.Pointer {
+						ft = ft.Elem()
+					}
+					if !sf.IsExported() && ft.Kind() != reflect.Struct {
+						// ignore embedded fields of unexported non-struct types
+						continue
+					}
+					// unexported embedded structs may still have exported fields
+				} else if !sf.IsExported() || sf.Type == fieldSetType {
+					// skip non-exported fields, and fields recording presence
+					continue
+				}
+				name, tagged, ok := c.fieldName(sf)
+				if !ok {
+					continue
+				}
+				index := append(slices.Clone(cand.index), i)
+
+				ft := sf.Type
+				if ft.Name() == "" && ft.Kind() == reflect.Pointer {
+					ft = ft.Elem()
+				}
+				if sf.Anonymous && !tagged && ft.Kind() == reflect.Struct {
+					// promote the fields of the embedded struct
+					nextCount[ft]++
+					if nextCount[ft] == 1 {
+						next = append(next, candidate{typ: ft, index: index})
+					}
+					continue
+				}
+				if !sf.IsExported() {
+					continue
+				}
+
+				sf.Index = index
+				fields = append(fields, &fieldInfo{StructField: sf, name: name, tagged: tagged, required: c.hasTagOption(sf, "required")})
+				if count[cand.typ] > 1 {
+					// the same struct is embedded more than once at this depth,
+					// add a duplicate so the field gets annihilated below
+					fields = append(fields, fields[len(fields)-1])
+				}
+			}
+		}
+	}
+
+	// sort by name, then depth, then tagged first, so the dominant field of
+	// each name comes first
+	slices.SortFunc(fields, func(a, b *fieldInfo) int {
+		if r := cmp.Compare(a.name, b.name); r != 0 {
+			return r
+		}
+		if r := cmp.Compare(len(a.Index), len(b.Index)); r != 0 {
+			return r
+		}
+		if a.tagged != b.tagged {
+			if a.tagged {
+				return -1
+			}
+			return 1
+		}
+		return slices.Compare(a.Index, b.Index)
+	})
+
+	res := fields[:0]
+	for i := 0; i < len(fields); {
+		j := i + 1
+		for j < len(fields) && fields[j].name == fields[i].name {
+			j++
+		}
+		if f, ok := dominantField(fields[i:j]); ok {
+			res = append(res, f)
+		}
+		i = j
+	}
+
+	slices.SortFunc(res, func(a, b *fieldInfo) int {
+		return slices.Compare(a.Index, b.Index)
+	})
+	return res
+}
+
+// dominantField returns the field that wins among fields sharing the same
+// name, sorted as done by structFields, or false if none does
+func dominantField(fields []*fieldInfo) (*fieldInfo, bool) {
+	if len(fields) > 1 && len(fields[0].Index) == len(fields[1].Index) && fields[0].tagged == fields[1].tagged {
+		return nil, false
+	}
+	return fields[0], true
+}
+
+// fieldByIndex returns the field of v at the given index sequence, or false if
+// the field is reached through a nil embedded pointer
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// fieldByIndexAlloc returns the field of v at the given index sequence,
+// allocating nil embedded pointers on the way
+func fieldByIndexAlloc(v reflect.Value, index []int) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, fmt.Errorf("%w: cannot set embedded pointer to unexported struct %s", ErrAssignImpossible, v.Type().Elem())
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, nil
+}
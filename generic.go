@@ -0,0 +1,313 @@
+package typutil
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+
+	"github.com/KarpelesLab/pjson"
+)
+
+// generic is the destination type used when building generic values, made of
+// map[string]any, []any and basic types, from structs and other Go values.
+type generic any
+
+var (
+	genericType    = reflect.TypeFor[generic]()
+	genericMapType = reflect.TypeFor[map[string]any]()
+)
+
+// basicTypes holds the unnamed type of each basic kind, used to turn named
+// types such as `type Status int` into their basic equivalent
+var basicTypes = map[reflect.Kind]reflect.Type{
+	reflect.Bool:    reflect.TypeFor[bool](),
+	reflect.Int:     reflect.TypeFor[int](),
+	reflect.Int8:    reflect.TypeFor[int8](),
+	reflect.Int16:   reflect.TypeFor[int16](),
+	reflect.Int32:   reflect.TypeFor[int32](),
+	reflect.Int64:   reflect.TypeFor[int64](),
+	reflect.Uint:    reflect.TypeFor[uint](),
+	reflect.Uint8:   reflect.TypeFor[uint8](),
+	reflect.Uint16:  reflect.TypeFor[uint16](),
+	reflect.Uint32:  reflect.TypeFor[uint32](),
+	reflect.Uint64:  reflect.TypeFor[uint64](),
+	reflect.Uintptr: reflect.TypeFor[uintptr](),
+	reflect.Float32: reflect.TypeFor[float32](),
+	reflect.Float64: reflect.TypeFor[float64](),
+	reflect.String:  reflect.TypeFor[string](),
+}
+
+// isGeneric returns true if t is the empty interface, in which case values
+// converted from structs are turned into generic values
+func isGeneric(t reflect.Type) bool {
+	return t.Kind() == reflect.Interface && t.NumMethod() == 0
+}
+
+// toGeneric returns the generic equivalent of v, see makeAssignGeneric
+func (c *Converter) toGeneric(v reflect.Value) (any, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+	f, err := c.getAssignFunc(genericType, v.Type())
+	if err != nil {
+		return nil, err
+	}
+	var res any
+	if err := f(reflect.ValueOf(&res).Elem(), v); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// makeAssignGeneric returns an assignFunc setting an empty interface dst to the
+// generic equivalent of src, following what pjson would encode: pointers are
+// followed, structs become map[string]any, slices and arrays become []any, and
+// types implementing json.Marshaler or encoding.TextMarshaler are encoded.
+func (c *Converter) makeAssignGeneric(srct reflect.Type) (assignFunc, error) {
+	if srct.Kind() == reflect.Interface {
+		// resolved at runtime from the concrete type
+		return func(dst, src reflect.Value) error {
+			if src.IsNil() {
+				dst.SetZero()
+				return nil
+			}
+			src = src.Elem()
+			f, err := c.getAssignFunc(genericType, src.Type())
+			if err != nil {
+				return err
+			}
+			return f(dst, src)
+		}, nil
+	}
+
+	if srct.Implements(jsonMarshalerType) {
+		f := func(dst, src reflect.Value) error {
+			if src.Kind() == reflect.Pointer && src.IsNil() {
+				dst.SetZero()
+				return nil
+			}
+			data, err := pjson.Marshal(src.Interface())
+			if err != nil {
+				return err
+			}
+			var v any
+			if err := pjson.Unmarshal(data, &v); err != nil {
+				return err
+			}
+			setGeneric(dst, v)
+			return nil
+		}
+		return f, nil
+	}
+	if srct.Implements(textMarshalerType) {
+		f := func(dst, src reflect.Value) error {
+			if src.Kind() == reflect.Pointer && src.IsNil() {
+				dst.SetZero()
+				return nil
+			}
+			text, err := src.Interface().(encoding.TextMarshaler).MarshalText()
+			if err != nil {
+				return err
+			}
+			dst.Set(reflect.ValueOf(string(text)))
+			return nil
+		}
+		return f, nil
+	}
+
+	switch srct.Kind() {
+	case reflect.Pointer:
+		ef, err := c.getAssignFunc(genericType, srct.Elem())
+		if err != nil {
+			return nil, err
+		}
+		f := func(dst, src reflect.Value) error {
+			if src.IsNil() {
+				dst.SetZero()
+				return nil
+			}
+			return ef(dst, src.Elem())
+		}
+		return f, nil
+	case reflect.Struct:
+		mf, err := c.getAssignFunc(genericMapType, srct)
+		if err != nil {
+			return nil, err
+		}
+		f := func(dst, src reflect.Value) error {
+			m := reflect.New(genericMapType).Elem()
+			if err := mf(m, src); err != nil {
+				return err
+			}
+			dst.Set(m)
+			return nil
+		}
+		return f, nil
+	case reflect.Map:
+		return c.makeGenericMap(srct)
+	case reflect.Slice:
+		if srct.Elem().Kind() == reflect.Uint8 {
+			// byte slices are kept as is
+			f := func(dst, src reflect.Value) error {
+				if src.IsNil() {
+					dst.SetZero()
+					return nil
+				}
+				dst.Set(reflect.ValueOf(src.Bytes()))
+				return nil
+			}
+			return f, nil
+		}
+		return c.makeGenericSlice(srct)
+	case reflect.Array:
+		return c.makeGenericSlice(srct)
+	}
+
+	basic, ok := basicTypes[srct.Kind()]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s has no generic equivalent", ErrAssignImpossible, srct)
+	}
+	f := func(dst, src reflect.Value) error {
+		dst.Set(src.Convert(basic))
+		return nil
+	}
+	return f, nil
+}
+
+// makeGenericMap returns an assignFunc converting a map to map[string]any,
+// with keys converted to strings and values to their generic equivalent
+func (c *Converter) makeGenericMap(srct reflect.Type) (assignFunc, error) {
+	kf, err := c.getAssignFunc(stringType, srct.Key())
+	if err != nil {
+		return nil, err
+	}
+	vf, err := c.getAssignFunc(genericType, srct.Elem())
+	if err != nil {
+		return nil, err
+	}
+
+	f := func(dst, src reflect.Value) error {
+		if src.IsNil() {
+			dst.SetZero()
+			return nil
+		}
+		m := reflect.MakeMapWithSize(genericMapType, src.Len())
+		ec := &errorCollector{c: c}
+		iter := src.MapRange()
+		for iter.Next() {
+			dk := reflect.New(stringType).Elem()
+			dv := reflect.New(genericMapType.Elem()).Elem()
+			seg := fmt.Sprintf("[%v]", iter.Key())
+			if err := kf(dk, iter.Key()); err != nil {
+				if err := ec.add(seg, dk, iter.Key(), err); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := vf(dv, iter.Value()); err != nil {
+				if err := ec.add(seg, dv, iter.Value(), err); err != nil {
+					return err
+				}
+				continue
+			}
+			m.SetMapIndex(dk, dv)
+		}
+		dst.Set(m)
+		return ec.result()
+	}
+	return f, nil
+}
+
+// makeGenericSlice returns an assignFunc converting a slice or an array to
+// []any, with values converted to their generic equivalent
+func (c *Converter) makeGenericSlice(srct reflect.Type) (assignFunc, error) {
+	vf, err := c.getAssignFunc(genericType, srct.Elem())
+	if err != nil {
+		return nil, err
+	}
+	nilable := srct.Kind() == reflect.Slice
+
+	f := func(dst, src reflect.Value) error {
+		if nilable && src.IsNil() {
+			dst.SetZero()
+			return nil
+		}
+		res := make([]any, src.Len())
+		ec := &errorCollector{c: c}
+		for i := range res {
+			dv := reflect.ValueOf(&res[i]).Elem()
+			if err := vf(dv, src.Index(i)); err != nil {
+				if err := ec.add(fmt.Sprintf("[%d]", i), dv, src.Index(i), err); err != nil {
+					return err
+				}
+			}
+		}
+		dst.Set(reflect.ValueOf(res))
+		return ec.result()
+	}
+	return f, nil
+}
+
+// setGeneric sets dst to v, or to a nil interface if v is nil
+func setGeneric(dst reflect.Value, v any) {
+	if v == nil {
+		dst.SetZero()
+		return
+	}
+	dst.Set(reflect.ValueOf(v))
+}
+
+// makeOmitFunc returns a function reporting whether the value of the struct
+// field f should be omitted when converting to a map, based on the omitempty
+// and omitzero tag options, or nil if the field is never omitted.
+func (c *Converter) makeOmitFunc(f reflect.StructField) func(v reflect.Value) bool {
+	omitEmpty := c.hasTagOption(f, "omitempty")
+	omitZero := c.hasTagOption(f, "omitzero")
+	if !omitEmpty && !omitZero {
+		return nil
+	}
+
+	var isZero func(v reflect.Value) bool
+	if omitZero {
+		isZero = zeroFunc(f.Type)
+	}
+	return func(v reflect.Value) bool {
+		return (omitEmpty && isEmptyValue(v)) || (omitZero && isZero(v))
+	}
+}
+
+// zeroFunc returns a function reporting whether a value of type t is zero,
+// using its IsZero method if it has one, like encoding/json
+func zeroFunc(t reflect.Type) func(v reflect.Value) bool {
+	m, ok := t.MethodByName("IsZero")
+	if !ok || m.Type.NumIn() != 1 || m.Type.NumOut() != 1 || m.Type.Out(0).Kind() != reflect.Bool {
+		return reflect.Value.IsZero
+	}
+	isPtr := t.Kind() == reflect.Pointer || t.Kind() == reflect.Interface
+	return func(v reflect.Value) bool {
+		if isPtr && v.IsNil() {
+			return true
+		}
+		return v.Method(m.Index).Call(nil)[0].Bool()
+	}
+}
+
+// isEmptyValue returns true if v is empty as defined by the omitempty option
+// of encoding/json
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Pointer:
+		return v.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package typutil_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/KarpelesLab/typutil"
+)
+
+func TestStructToGenericMap(t *testing.T) {
+	type Level int
+	type Address struct {
+		City string `json:"city"`
+		Zip  string `json:"zip,omitempty"`
+	}
+	type Person struct {
+		Name     string            `json:"name"`
+		Level    Level             `json:"level"`
+		Home     *Address          `json:"home"`
+		Work     *Address          `json:"work"`
+		Previous []Address         `json:"previous"`
+		Tags     map[string]*Level `json:"tags,omitempty"`
+		Nick     string            `json:"nick,omitempty"`
+		Born     time.Time         `json:"born,omitzero"`
+		Seen     time.Time         `json:"seen"`
+		Extra    any               `json:"extra"`
+		Secret   string            `json:"-"`
+		internal string
+	}
+
+	lvl := Level(3)
+	p := Person{
+		Name:     "alice",
+		Level:    2,
+		Home:     &Address{City: "Paris", Zip: "75001"},
+		Previous: []Address{{City: "Lyon"}},
+		Tags:     map[string]*Level{"x": &lvl},
+		Seen:     time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Extra:    []Address{{City: "Nice"}},
+		Secret:   "s",
+		internal: "i",
+	}
+
+	m, err := typutil.As[map[string]any](p)
+	if err != nil {
+		t.Errorf("struct to map failed: %s", err)
+		return
+	}
+	expected := map[string]any{
+		"name":     "alice",
+		"level":    2,
+		"home":     map[string]any{"city": "Paris", "zip": "75001"},
+		"work":     nil,
+		"previous": []any{map[string]any{"city": "Lyon"}},
+		"tags":     map[string]any{"x": 3},
+		"seen":     "2024-01-02T03:04:05Z",
+		"extra":    []any{map[string]any{"city": "Nice"}},
+	}
+	if !reflect.DeepEqual(m, expected) {
+		t.Errorf("unexpected value %#v", m)
+	}
+
+	// other destination element types are unaffected
+	ms, err := typutil.As[map[string]string](Address{City: "Paris"})
+	if err != nil || !reflect.DeepEqual(ms, map[string]string{"city": "Paris"}) {
+		t.Errorf("unexpected value %v (err=%v)", ms, err)
+	}
+}
+
+func TestStructToGenericMapRecursive(t *testing.T) {
+	type Node struct {
+		Value    int     `json:"value"`
+		Children []*Node `json:"children,omitempty"`
+	}
+
+	m, err := typutil.As[map[string]any](Node{Value: 1, Children: []*Node{{Value: 2}, nil}})
+	if err != nil {
+		t.Errorf("struct to map failed: %s", err)
+		return
+	}
+	expected := map[string]any{
+		"value":    1,
+		"children": []any{map[string]any{"value": 2}, nil},
+	}
+	if !reflect.DeepEqual(m, expected) {
+		t.Errorf("unexpected value %#v", m)
+	}
+}
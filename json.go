@@ -1,9 +1,18 @@
 package typutil
 
 import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
 	"github.com/KarpelesLab/pjson"
 )
 
+var (
+	jsonMarshalerType   = reflect.TypeFor[json.Marshaler]()
+	jsonUnmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+)
+
 // RawJsonMessage is similar to json.RawMessage, but also implements additional functionality.
 //
 // This type represents a raw JSON message as a byte slice. It provides:
@@ -46,3 +55,68 @@ func (m *RawJsonMessage) UnmarshalJSON(data []byte) error {
 func (m RawJsonMessage) AssignTo(v any) error {
 	return pjson.Unmarshal([]byte(m), v)
 }
+
+// JSONFallback is a functional option for NewConverter that enables a JSON
+// round-trip when converting to a type implementing json.Unmarshaler or from a
+// type implementing json.Marshaler.
+//
+// If no direct conversion exists, or if it fails, the source is marshaled with
+// pjson.Marshal and the result unmarshaled into the destination with
+// pjson.Unmarshal. If both fail, the returned error wraps both the original
+// error and the fallback error.
+func JSONFallback(c *Converter) {
+	c.jsonFallback = true
+}
+
+// usesJSON returns true if a conversion from srct to dstt can be performed by
+// marshaling and unmarshaling JSON through custom methods
+func usesJSON(dstt, srct reflect.Type) bool {
+	if dstt.Kind() == reflect.Pointer || srct.Kind() == reflect.Pointer {
+		// pointers are resolved first, the fallback applies to the pointed types
+		return false
+	}
+	if srct.Kind() == reflect.Interface {
+		// resolved at runtime from the concrete type
+		return false
+	}
+	return reflect.PointerTo(dstt).Implements(jsonUnmarshalerType) ||
+		srct.Implements(jsonMarshalerType) ||
+		reflect.PointerTo(srct).Implements(jsonMarshalerType)
+}
+
+// makeJSONFallback wraps f (or the error returned when building it) so that
+// failed conversions are retried through JSON
+func (c *Converter) makeJSONFallback(dstt, srct reflect.Type, f assignFunc, err error) assignFunc {
+	validator := c.getValidatorForType(dstt)
+	srcptr := !srct.Implements(jsonMarshalerType) && reflect.PointerTo(srct).Implements(jsonMarshalerType)
+
+	return func(dst, src reflect.Value) error {
+		origErr := err
+		if f != nil {
+			origErr = f(dst, src)
+			if origErr == nil {
+				return nil
+			}
+		}
+
+		if !dst.CanAddr() {
+			return ErrDestinationNotAddressable
+		}
+		srcv := src.Interface()
+		if srcptr {
+			// MarshalJSON is declared on the pointer receiver
+			p := reflect.New(srct)
+			p.Elem().Set(src)
+			srcv = p.Interface()
+		}
+		data, jerr := pjson.Marshal(srcv)
+		if jerr == nil {
+			jerr = pjson.Unmarshal(data, dst.Addr().Interface())
+		}
+		if jerr != nil {
+			return fmt.Errorf("%w (json fallback: %w)", origErr, jerr)
+		}
+
+		return validator.validate(c, dst)
+	}
+}
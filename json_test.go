@@ -2,7 +2,11 @@ package typutil_test
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/KarpelesLab/typutil"
@@ -128,3 +132,91 @@ func TestRawJsonMessageInvalidJSON(t *testing.T) {
 		t.Error("Expected error for invalid JSON, got nil")
 	}
 }
+
+// money only knows how to decode itself from JSON
+type money struct {
+	cents int64
+}
+
+func (m *money) UnmarshalJSON(b []byte) error {
+	var f float64
+	if err := json.Unmarshal(b, &f); err != nil {
+		var s string
+		if err := json.Unmarshal(b, &s); err != nil {
+			return errors.New("invalid amount")
+		}
+		if _, err := fmt.Sscanf(s, "%f", &f); err != nil {
+			return errors.New("invalid amount")
+		}
+	}
+	m.cents = int64(f*100 + 0.5)
+	return nil
+}
+
+// point encodes itself as a JSON array
+type point struct {
+	x, y int
+}
+
+func (p *point) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]int{p.x, p.y})
+}
+
+func TestJSONFallback(t *testing.T) {
+	conv := typutil.NewConverter(typutil.JSONFallback)
+
+	m, err := typutil.AsWith[money](conv, "12.34")
+	if err != nil || m.cents != 1234 {
+		t.Errorf("string to money: got %d (err=%v)", m.cents, err)
+	}
+
+	m, err = typutil.AsWith[money](conv, 5)
+	if err != nil || m.cents != 500 {
+		t.Errorf("int to money: got %d (err=%v)", m.cents, err)
+	}
+
+	p, err := typutil.AsWith[[]int](conv, point{3, 4})
+	if err != nil || len(p) != 2 || p[0] != 3 || p[1] != 4 {
+		t.Errorf("point to []int: got %v (err=%v)", p, err)
+	}
+
+	type Order struct {
+		Total *money
+	}
+	o, err := typutil.AsWith[Order](conv, map[string]any{"Total": "1.50"})
+	if err != nil || o.Total == nil || o.Total.cents != 150 {
+		t.Errorf("map to Order: got %+v (err=%v)", o, err)
+	}
+	o, err = typutil.AsWith[Order](conv, map[string]float64{"Total": 1.5})
+	if err != nil || o.Total == nil || o.Total.cents != 150 {
+		t.Errorf("typed map to Order: got %+v (err=%v)", o, err)
+	}
+
+	type Invoice struct {
+		Total money
+	}
+	type Src struct {
+		Total float64
+	}
+	i, err := typutil.AsWith[Invoice](conv, map[string]float64{"Total": 1.5})
+	if err != nil || i.Total.cents != 150 {
+		t.Errorf("typed map to Invoice: got %+v (err=%v)", i, err)
+	}
+	i, err = typutil.AsWith[Invoice](conv, Src{Total: 2.5})
+	if err != nil || i.Total.cents != 250 {
+		t.Errorf("struct to Invoice: got %+v (err=%v)", i, err)
+	}
+
+	// without the option, no conversion exists
+	if _, err := typutil.As[money]("12.34"); err == nil {
+		t.Errorf("expected error without JSONFallback")
+	}
+
+	// both errors are reported
+	_, err = typutil.AsWith[money](conv, true)
+	if !errors.Is(err, typutil.ErrAssignImpossible) {
+		t.Errorf("expected original error to be wrapped, got %v", err)
+	} else if !strings.Contains(err.Error(), "invalid amount") {
+		t.Errorf("expected fallback error to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,725 @@
+package typutil
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/KarpelesLab/pjson"
+)
+
+// PatchOp is an operation of a RFC 6902 JSON Patch. Path and From are JSON
+// Pointers (RFC 6901), and Value is only used by the add, replace and test
+// operations.
+type PatchOp struct {
+	Op    string `json:"op"`             // add, remove, replace, move, copy or test
+	Path  string `json:"path"`           // location of the target value
+	From  string `json:"from,omitempty"` // location of the source value for move and copy
+	Value any    `json:"value,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler, including the value member for the
+// operations requiring it even when it is null.
+func (op PatchOp) MarshalJSON() ([]byte, error) {
+	m := map[string]any{"op": op.Op, "path": op.Path}
+	switch op.Op {
+	case "add", "replace", "test":
+		m["value"] = op.Value
+	case "move", "copy":
+		m["from"] = op.From
+	}
+	return pjson.Marshal(m)
+}
+
+type diffOption func(*differ)
+
+// DiffStrict is an option for Diff that compares values with reflect.DeepEqual
+// instead of Equal, so that for example 1 and "1" are reported as different.
+func DiffStrict(d *differ) {
+	d.strict = true
+}
+
+// DiffMoveCopy is an option for Diff that emits move operations for object
+// members that were renamed, and copy operations for objects and arrays
+// duplicated from an unchanged value, instead of remove and add operations.
+func DiffMoveCopy(d *differ) {
+	d.moveCopy = true
+}
+
+// Diff returns the RFC 6902 JSON Patch operations turning a into b.
+//
+// Both values are first converted to generic values (see Assign), so structs,
+// maps and slices can be compared with each other. Objects are compared member
+// by member, arrays element by element, and other values using the loose
+// semantics of Equal unless the DiffStrict option is given. Paths are JSON
+// Pointers, such as "/address/zip" or "/tags/2".
+//
+// Example:
+//
+//	ops := typutil.Diff(before, after)
+//	// [{Op: "replace", Path: "/name", Value: "Bob"}, {Op: "remove", Path: "/tags/1"}]
+func Diff(a, b any, opts ...diffOption) []PatchOp {
+	return defaultConverter.Diff(a, b, opts...)
+}
+
+// Diff returns the operations turning a into b using the converter's settings.
+// See the package-level Diff for details.
+func (c *Converter) Diff(a, b any, opts ...diffOption) []PatchOp {
+	d := &differ{}
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	d.diff("", c.toGenericOrSelf(a), c.toGenericOrSelf(b))
+	if d.moveCopy {
+		d.detectMoveCopy()
+	}
+	return d.ops
+}
+
+// toGenericOrSelf returns the generic equivalent of v, or v itself if it has
+// none, so it is compared as a whole
+func (c *Converter) toGenericOrSelf(v any) any {
+	g, err := c.toGeneric(reflect.ValueOf(v))
+	if err != nil {
+		return v
+	}
+	return g
+}
+
+// differ holds the state of a Diff call
+type differ struct {
+	strict    bool
+	moveCopy  bool
+	ops       []PatchOp
+	removed   map[int]any // values removed by the ops at the given positions
+	unchanged []PatchOp   // unchanged objects and arrays, as copy candidates
+}
+
+func (d *differ) diff(path string, a, b any) {
+	switch av := a.(type) {
+	case map[string]any:
+		if bv, ok := b.(map[string]any); ok {
+			d.diffObject(path, av, bv)
+			return
+		}
+	case []any:
+		if bv, ok := b.([]any); ok {
+			d.diffArray(path, av, bv)
+			return
+		}
+	}
+	if d.equal(a, b) {
+		return
+	}
+	d.ops = append(d.ops, PatchOp{Op: "replace", Path: path, Value: b})
+}
+
+func (d *differ) diffObject(path string, a, b map[string]any) {
+	if reflect.DeepEqual(a, b) {
+		d.addUnchanged(path, a)
+		return
+	}
+	for _, k := range slices.Sorted(maps.Keys(a)) {
+		if _, found := b[k]; !found {
+			d.remove(path+"/"+escapePointer(k), a[k])
+		}
+	}
+	for _, k := range slices.Sorted(maps.Keys(b)) {
+		sub := path + "/" + escapePointer(k)
+		if av, found := a[k]; found {
+			d.diff(sub, av, b[k])
+		} else {
+			d.ops = append(d.ops, PatchOp{Op: "add", Path: sub, Value: b[k]})
+		}
+	}
+}
+
+func (d *differ) diffArray(path string, a, b []any) {
+	if reflect.DeepEqual(a, b) {
+		d.addUnchanged(path, a)
+		return
+	}
+	n := min(len(a), len(b))
+	for i := 0; i < n; i++ {
+		d.diff(path+"/"+strconv.Itoa(i), a[i], b[i])
+	}
+	// remove from the end so indexes stay valid
+	for i := len(a) - 1; i >= n; i-- {
+		d.remove(path+"/"+strconv.Itoa(i), a[i])
+	}
+	for i := n; i < len(b); i++ {
+		d.ops = append(d.ops, PatchOp{Op: "add", Path: path + "/" + strconv.Itoa(i), Value: b[i]})
+	}
+}
+
+func (d *differ) remove(path string, v any) {
+	if d.moveCopy {
+		if d.removed == nil {
+			d.removed = make(map[int]any)
+		}
+		d.removed[len(d.ops)] = v
+	}
+	d.ops = append(d.ops, PatchOp{Op: "remove", Path: path})
+}
+
+func (d *differ) addUnchanged(path string, v any) {
+	if d.moveCopy && path != "" {
+		d.unchanged = append(d.unchanged, PatchOp{Path: path, Value: v})
+	}
+}
+
+// equal compares two leaf values
+func (d *differ) equal(a, b any) bool {
+	if d.strict {
+		return reflect.DeepEqual(a, b)
+	}
+	if reflect.TypeOf(a) != reflect.TypeOf(b) && (isContainer(a) || isContainer(b)) {
+		return false
+	}
+	return Equal(a, b)
+}
+
+// isContainer returns true if v is a generic object or array
+func isContainer(v any) bool {
+	switch v.(type) {
+	case map[string]any, []any:
+		return true
+	}
+	return false
+}
+
+// detectMoveCopy replaces add operations on object members by move operations
+// when the same value was removed from another object member, or by copy
+// operations when the value is an unchanged object or array
+func (d *differ) detectMoveCopy() {
+	var res []PatchOp
+	moved := make(map[int]bool)
+
+	for i, op := range d.ops {
+		if op.Op != "add" || !isMemberPath(op.Path) {
+			continue
+		}
+		for _, j := range slices.Sorted(maps.Keys(d.removed)) {
+			if j < i && !moved[j] && isMemberPath(d.ops[j].Path) && reflect.DeepEqual(d.removed[j], op.Value) {
+				moved[j] = true
+				d.ops[i] = PatchOp{Op: "move", From: d.ops[j].Path, Path: op.Path}
+				break
+			}
+		}
+		if d.ops[i].Op != "add" || !isContainer(op.Value) {
+			continue
+		}
+		for _, u := range d.unchanged {
+			if reflect.DeepEqual(u.Value, op.Value) {
+				d.ops[i] = PatchOp{Op: "copy", From: u.Path, Path: op.Path}
+				break
+			}
+		}
+	}
+
+	for i, op := range d.ops {
+		if !moved[i] {
+			res = append(res, op)
+		}
+	}
+	d.ops = res
+}
+
+// isMemberPath returns true if the last token of the JSON Pointer path is not
+// an array index
+func isMemberPath(path string) bool {
+	last := path[strings.LastIndexByte(path, '/')+1:]
+	_, err := strconv.Atoi(last)
+	return err != nil && last != "-"
+}
+
+// escapePointer escapes a JSON Pointer reference token as defined in RFC 6901
+func escapePointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
+
+// ApplyPatch applies the RFC 6902 JSON Patch operations ops to the value
+// pointed by dst, which can be a struct, map, slice or any mix of these.
+//
+// Paths are JSON Pointers where struct fields are matched like map keys are in
+// Assign. Values are converted to the type of their target as with Assign.
+// As struct fields cannot be deleted, removing one resets it to its zero value.
+//
+// The operations are applied to a copy of dst, which is only updated if all
+// operations succeed. Only the maps, slices and pointers leading to modified
+// values are copied, other values are kept as they are. Test operations compare values with the loose
+// semantics of Equal, after converting the expected value to the type of the
+// tested value.
+//
+// Example:
+//
+//	err := typutil.ApplyPatch(&user, []typutil.PatchOp{
+//		{Op: "test", Path: "/version", Value: 3},
+//		{Op: "replace", Path: "/name", Value: "Bob"},
+//		{Op: "add", Path: "/tags/-", Value: "admin"},
+//	})
+func ApplyPatch(dst any, ops []PatchOp) error {
+	return defaultConverter.ApplyPatch(dst, ops)
+}
+
+// ApplyPatch applies ops to the value pointed by dst using the converter's
+// settings. See the package-level ApplyPatch for details.
+func (c *Converter) ApplyPatch(dst any, ops []PatchOp) error {
+	vdst := reflect.ValueOf(dst)
+	if vdst.Kind() != reflect.Pointer || vdst.IsNil() {
+		return ErrAssignDestNotPointer
+	}
+
+	// work on a copy so dst is left unchanged if an operation fails
+	p := &patcher{c: c, owned: make(map[uintptr]bool)}
+	work := reflect.New(vdst.Type().Elem()).Elem()
+	work.Set(vdst.Elem())
+
+	for n, op := range ops {
+		if err := p.apply(work, op); err != nil {
+			return fmt.Errorf("operation %d (%s %s): %w", n, op.Op, op.Path, err)
+		}
+	}
+	vdst.Elem().Set(work)
+	return nil
+}
+
+// patcher applies patch operations on a shallow copy of a value. Maps, slices
+// and pointers are copied on the way to each modified value, leaving the
+// original value and the parts the patch does not touch as they are.
+type patcher struct {
+	c     *Converter
+	owned map[uintptr]bool // maps, slices and pointers copied by the patch
+}
+
+func (p *patcher) apply(root reflect.Value, op PatchOp) error {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return err
+	}
+
+	switch op.Op {
+	case "add", "replace":
+		return p.set(root, path, reflect.ValueOf(op.Value), op.Op == "add")
+	case "remove":
+		_, err := p.remove(root, path)
+		return err
+	case "move", "copy":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return err
+		}
+		var v reflect.Value
+		if op.Op == "move" {
+			if op.From == op.Path {
+				return nil
+			}
+			if strings.HasPrefix(op.Path, op.From+"/") {
+				return fmt.Errorf("%w: cannot move a value into itself", ErrPatchOperation)
+			}
+			v, err = p.remove(root, from)
+		} else {
+			v, err = p.c.patchGet(root, from)
+			v = DeepCloneReflect(v)
+		}
+		if err != nil {
+			return err
+		}
+		return p.set(root, path, v, true)
+	case "test":
+		v, err := p.c.patchGet(root, path)
+		if err != nil {
+			return err
+		}
+		expected := reflect.New(v.Type()).Elem()
+		if err := p.c.patchAssign(expected, reflect.ValueOf(op.Value)); err != nil {
+			return fmt.Errorf("%w: %w", ErrPatchTestFailed, err)
+		}
+		if len(p.c.Diff(v.Interface(), expected.Interface())) > 0 {
+			return ErrPatchTestFailed
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w %q", ErrPatchOperation, op.Op)
+	}
+}
+
+// parsePointer returns the reference tokens of a JSON Pointer
+func parsePointer(s string) ([]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+	if s[0] != '/' {
+		return nil, fmt.Errorf("%w: invalid JSON pointer %q", ErrPatchOperation, s)
+	}
+	tokens := strings.Split(s[1:], "/")
+	unescape := strings.NewReplacer("~1", "/", "~0", "~")
+	for i, tok := range tokens {
+		tokens[i] = unescape.Replace(tok)
+	}
+	return tokens, nil
+}
+
+// patchGet returns the value at path, without modifying root
+func (c *Converter) patchGet(v reflect.Value, path []string) (reflect.Value, error) {
+	for _, key := range path {
+		for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return reflect.Value{}, ErrPatchPath
+			}
+			v = v.Elem()
+		}
+		switch v.Kind() {
+		case reflect.Struct:
+			f, ok := c.patchField(v.Type(), key)
+			if !ok {
+				return reflect.Value{}, ErrPatchPath
+			}
+			if v, ok = fieldByIndex(v, f.Index); !ok {
+				return reflect.Value{}, ErrPatchPath
+			}
+		case reflect.Map:
+			k, err := c.patchKey(v.Type(), key)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			if v = v.MapIndex(k); !v.IsValid() {
+				return reflect.Value{}, ErrPatchPath
+			}
+		case reflect.Slice, reflect.Array:
+			i, err := patchIndex(key, v.Len(), false)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			v = v.Index(i)
+		default:
+			return reflect.Value{}, ErrPatchPath
+		}
+	}
+	return v, nil
+}
+
+// set sets the value at path to val. If insert is true, val is inserted in
+// arrays and may create new map keys, otherwise the target must exist.
+func (p *patcher) set(root reflect.Value, path []string, val reflect.Value, insert bool) error {
+	if len(path) == 0 {
+		return p.assign(p.c, root, val)
+	}
+	return p.walk(root, path, func(container reflect.Value, key string) error {
+		switch container.Kind() {
+		case reflect.Struct:
+			f, ok := p.c.patchField(container.Type(), key)
+			if !ok {
+				return ErrPatchPath
+			}
+			fv, err := p.field(container, f.Index)
+			if err != nil {
+				return err
+			}
+			fc, err := p.c.fieldConverter(f.StructField)
+			if err != nil {
+				return err
+			}
+			return p.assign(fc, fv, val)
+		case reflect.Map:
+			k, err := p.c.patchKey(container.Type(), key)
+			if err != nil {
+				return err
+			}
+			if !insert && !container.MapIndex(k).IsValid() {
+				return ErrPatchPath
+			}
+			v := reflect.New(container.Type().Elem()).Elem()
+			if err := p.c.patchAssign(v, val); err != nil {
+				return err
+			}
+			if container.IsNil() {
+				container.Set(reflect.MakeMap(container.Type()))
+				p.owned[container.Pointer()] = true
+			}
+			p.own(container)
+			container.SetMapIndex(k, v)
+			return nil
+		case reflect.Slice:
+			if !insert {
+				break
+			}
+			i, err := patchIndex(key, container.Len(), true)
+			if err != nil {
+				return err
+			}
+			v := reflect.New(container.Type().Elem()).Elem()
+			if err := p.c.patchAssign(v, val); err != nil {
+				return err
+			}
+			res := reflect.MakeSlice(container.Type(), 0, container.Len()+1)
+			res = reflect.AppendSlice(res, container.Slice(0, i))
+			res = reflect.Append(res, v)
+			res = reflect.AppendSlice(res, container.Slice(i, container.Len()))
+			container.Set(res)
+			p.owned[res.Pointer()] = true
+			return nil
+		case reflect.Array:
+			if insert {
+				return fmt.Errorf("%w: cannot add elements to an array", ErrPatchOperation)
+			}
+		}
+		return p.child(container, key, func(child reflect.Value) error {
+			return p.assign(p.c, child, val)
+		})
+	})
+}
+
+// remove removes the value at path and returns it. Struct fields and the root
+// value are reset to their zero value.
+func (p *patcher) remove(root reflect.Value, path []string) (reflect.Value, error) {
+	if len(path) == 0 {
+		old := reflect.New(root.Type()).Elem()
+		old.Set(root)
+		root.SetZero()
+		return old, nil
+	}
+	var old reflect.Value
+	err := p.walk(root, path, func(container reflect.Value, key string) error {
+		switch container.Kind() {
+		case reflect.Map:
+			k, err := p.c.patchKey(container.Type(), key)
+			if err != nil {
+				return err
+			}
+			if old = container.MapIndex(k); !old.IsValid() {
+				return ErrPatchPath
+			}
+			p.own(container)
+			container.SetMapIndex(k, reflect.Value{})
+			return nil
+		case reflect.Slice:
+			i, err := patchIndex(key, container.Len(), false)
+			if err != nil {
+				return err
+			}
+			old = container.Index(i)
+			res := reflect.MakeSlice(container.Type(), 0, container.Len()-1)
+			res = reflect.AppendSlice(res, container.Slice(0, i))
+			res = reflect.AppendSlice(res, container.Slice(i+1, container.Len()))
+			container.Set(res)
+			p.owned[res.Pointer()] = true
+			return nil
+		case reflect.Array:
+			return fmt.Errorf("%w: cannot remove elements from an array", ErrPatchOperation)
+		}
+		return p.child(container, key, func(child reflect.Value) error {
+			old = reflect.New(child.Type()).Elem()
+			old.Set(child)
+			child.SetZero()
+			return nil
+		})
+	})
+	return old, err
+}
+
+// walk calls fn with the container of the value at path, which is made
+// settable, and the last token of path. Values held by maps and interfaces are
+// copied, and stored back once fn returns.
+func (p *patcher) walk(v reflect.Value, path []string, fn func(container reflect.Value, key string) error) error {
+	return p.deref(v, func(v reflect.Value) error {
+		if len(path) == 1 {
+			return fn(v, path[0])
+		}
+		return p.child(v, path[0], func(child reflect.Value) error {
+			return p.walk(child, path[1:], fn)
+		})
+	})
+}
+
+// deref calls fn with the value pointed by v, following pointers and
+// interfaces
+func (p *patcher) deref(v reflect.Value, fn func(v reflect.Value) error) error {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return ErrPatchPath
+		}
+		p.own(v)
+		return p.deref(v.Elem(), fn)
+	case reflect.Interface:
+		if v.IsNil() {
+			return ErrPatchPath
+		}
+		tmp := reflect.New(v.Elem().Type()).Elem()
+		tmp.Set(v.Elem())
+		if err := p.deref(tmp, fn); err != nil {
+			return err
+		}
+		v.Set(tmp)
+		return nil
+	}
+	return fn(v)
+}
+
+// child calls fn with the settable member key of container
+func (p *patcher) child(container reflect.Value, key string, fn func(child reflect.Value) error) error {
+	switch container.Kind() {
+	case reflect.Struct:
+		f, ok := p.c.patchField(container.Type(), key)
+		if !ok {
+			return ErrPatchPath
+		}
+		fv, err := p.field(container, f.Index)
+		if err != nil {
+			return err
+		}
+		return fn(fv)
+	case reflect.Map:
+		k, err := p.c.patchKey(container.Type(), key)
+		if err != nil {
+			return err
+		}
+		cur := container.MapIndex(k)
+		if !cur.IsValid() {
+			return ErrPatchPath
+		}
+		v := reflect.New(container.Type().Elem()).Elem()
+		v.Set(cur)
+		if err := fn(v); err != nil {
+			return err
+		}
+		p.own(container)
+		container.SetMapIndex(k, v)
+		return nil
+	case reflect.Slice, reflect.Array:
+		i, err := patchIndex(key, container.Len(), false)
+		if err != nil {
+			return err
+		}
+		p.own(container)
+		return fn(container.Index(i))
+	}
+	return ErrPatchPath
+}
+
+// field returns the field of the struct v at the given index sequence,
+// copying or allocating embedded pointers on the way
+func (p *patcher) field(v reflect.Value, index []int) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if !v.CanSet() {
+				return reflect.Value{}, fmt.Errorf("%w: cannot set embedded pointer to unexported struct %s", ErrAssignImpossible, v.Type().Elem())
+			}
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+				p.owned[v.Pointer()] = true
+			}
+			p.own(v)
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, nil
+}
+
+// own replaces the map, slice or pointer v with a shallow copy so that its
+// content can be modified in place, unless the patch already copied it
+func (p *patcher) own(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Pointer:
+	default:
+		return
+	}
+	if v.IsNil() || p.owned[v.Pointer()] {
+		return
+	}
+	var cp reflect.Value
+	switch v.Kind() {
+	case reflect.Map:
+		cp = reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			cp.SetMapIndex(iter.Key(), iter.Value())
+		}
+	case reflect.Slice:
+		cp = reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		reflect.Copy(cp, v)
+	case reflect.Pointer:
+		cp = reflect.New(v.Type().Elem())
+		cp.Elem().Set(v.Elem())
+	}
+	v.Set(cp)
+	p.owned[cp.Pointer()] = true
+}
+
+// assign sets dst to val using the converter c. The value is converted into a
+// new value, as conversions may write through the pointers held by dst, and
+// when merging it starts from a deep copy of dst.
+func (p *patcher) assign(c *Converter, dst, val reflect.Value) error {
+	v := reflect.New(dst.Type()).Elem()
+	if c.merge {
+		v.Set(DeepCloneReflect(dst))
+	}
+	if err := c.patchAssign(v, val); err != nil {
+		return err
+	}
+	dst.Set(v)
+	return nil
+}
+
+// patchField returns the field of the struct type t matching key
+func (c *Converter) patchField(t reflect.Type, key string) (*fieldInfo, bool) {
+	var fold *fieldInfo
+	for _, f := range c.structFields(t) {
+		if f.name == key {
+			return f, true
+		}
+		if fold == nil && !c.caseSensitive && strings.EqualFold(f.name, key) {
+			fold = f
+		}
+	}
+	return fold, fold != nil
+}
+
+// patchKey converts key to the key type of the map type t
+func (c *Converter) patchKey(t reflect.Type, key string) (reflect.Value, error) {
+	f, err := c.getAssignFunc(t.Key(), stringType)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	k := reflect.New(t.Key()).Elem()
+	if err := f(k, reflect.ValueOf(key)); err != nil {
+		return reflect.Value{}, err
+	}
+	return k, nil
+}
+
+// patchIndex parses the array index key for an array of length n. If insert
+// is true, the index may be n or "-", which designates the end of the array.
+func patchIndex(key string, n int, insert bool) (int, error) {
+	if insert && key == "-" {
+		return n, nil
+	}
+	i, err := strconv.Atoi(key)
+	if err != nil || i < 0 || (len(key) > 1 && key[0] == '0') || i > n || (i == n && !insert) {
+		return 0, fmt.Errorf("%w: invalid index %q", ErrPatchPath, key)
+	}
+	return i, nil
+}
+
+// patchAssign sets dst to val converted to the type of dst, or to its zero
+// value if val is nil
+func (c *Converter) patchAssign(dst, val reflect.Value) error {
+	if val.Kind() == reflect.Interface {
+		val = val.Elem()
+	}
+	if isNull(val) {
+		dst.SetZero()
+		return nil
+	}
+	f, err := c.getAssignFunc(dst.Type(), val.Type())
+	if err != nil {
+		return err
+	}
+	return f(dst, val)
+}
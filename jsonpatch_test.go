@@ -0,0 +1,235 @@
+package typutil_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/KarpelesLab/typutil"
+)
+
+func TestDiff(t *testing.T) {
+	type Address struct {
+		City string `json:"city"`
+		Zip  string `json:"zip,omitempty"`
+	}
+	type User struct {
+		Name    string         `json:"name"`
+		Age     int            `json:"age"`
+		Address *Address       `json:"address"`
+		Tags    []string       `json:"tags"`
+		Extra   map[string]any `json:"extra,omitempty"`
+	}
+
+	a := User{Name: "alice", Age: 30, Address: &Address{City: "Paris", Zip: "75001"}, Tags: []string{"a", "b", "c"}}
+	b := User{Name: "Bob", Age: 30, Address: &Address{City: "Paris"}, Tags: []string{"a", "x"}, Extra: map[string]any{"a/b": 1}}
+
+	ops := typutil.Diff(a, b)
+	expected := []typutil.PatchOp{
+		{Op: "remove", Path: "/address/zip"},
+		{Op: "add", Path: "/extra", Value: map[string]any{"a/b": 1}},
+		{Op: "replace", Path: "/name", Value: "Bob"},
+		{Op: "replace", Path: "/tags/1", Value: "x"},
+		{Op: "remove", Path: "/tags/2"},
+	}
+	if !reflect.DeepEqual(ops, expected) {
+		t.Errorf("unexpected ops %+v", ops)
+	}
+
+	// structs and maps can be compared, with loose leaf comparisons
+	m := map[string]any{"name": "alice", "age": "30", "address": map[string]any{"city": "Paris", "zip": "75001"}, "tags": []any{"a", "b", "c"}}
+	if ops := typutil.Diff(a, m); len(ops) != 0 {
+		t.Errorf("expected no ops, got %+v", ops)
+	}
+	ops = typutil.Diff(a, m, typutil.DiffStrict)
+	if !reflect.DeepEqual(ops, []typutil.PatchOp{{Op: "replace", Path: "/age", Value: "30"}}) {
+		t.Errorf("unexpected ops %+v", ops)
+	}
+
+	// paths are escaped, and remove ops have no value in JSON
+	buf, _ := json.Marshal(typutil.Diff(map[string]any{"a/b": 1, "c~d": nil}, map[string]any{"c~d": nil}))
+	if string(buf) != `[{"op":"remove","path":"/a~1b"}]` {
+		t.Errorf("unexpected JSON %s", buf)
+	}
+	buf, _ = json.Marshal(typutil.Diff(map[string]any{"a": 1}, map[string]any{"a": nil}))
+	if string(buf) != `[{"op":"replace","path":"/a","value":null}]` {
+		t.Errorf("unexpected JSON %s", buf)
+	}
+}
+
+func TestDiffMoveCopy(t *testing.T) {
+	a := map[string]any{"old": "value", "base": map[string]any{"x": 1}}
+	b := map[string]any{"new": "value", "base": map[string]any{"x": 1}, "dup": map[string]any{"x": 1}}
+
+	ops := typutil.Diff(a, b, typutil.DiffMoveCopy)
+	expected := []typutil.PatchOp{
+		{Op: "copy", From: "/base", Path: "/dup"},
+		{Op: "move", From: "/old", Path: "/new"},
+	}
+	if !reflect.DeepEqual(ops, expected) {
+		t.Errorf("unexpected ops %+v", ops)
+	}
+}
+
+func TestApplyPatch(t *testing.T) {
+	type Address struct {
+		City string `json:"city"`
+		Zip  string `json:"zip"`
+	}
+	type User struct {
+		Name    string            `json:"name"`
+		Version int               `json:"version"`
+		Address *Address          `json:"address"`
+		Tags    []string          `json:"tags"`
+		Labels  map[string]string `json:"labels"`
+		Extra   map[string]any    `json:"extra"`
+	}
+
+	u := User{
+		Name:    "alice",
+		Version: 3,
+		Address: &Address{City: "Paris", Zip: "75001"},
+		Tags:    []string{"a", "b"},
+		Labels:  map[string]string{"env": "prod"},
+		Extra:   map[string]any{"list": []any{1.0, 2.0}},
+	}
+
+	var ops []typutil.PatchOp
+	err := json.Unmarshal([]byte(`[
+		{"op": "test", "path": "/version", "value": "3"},
+		{"op": "replace", "path": "/name", "value": "Bob"},
+		{"op": "add", "path": "/tags/-", "value": "c"},
+		{"op": "add", "path": "/tags/0", "value": "z"},
+		{"op": "remove", "path": "/tags/2"},
+		{"op": "add", "path": "/labels/a~1b", "value": "x"},
+		{"op": "move", "from": "/labels/env", "path": "/labels/stage"},
+		{"op": "copy", "from": "/address/city", "path": "/extra/city"},
+		{"op": "remove", "path": "/address/zip"},
+		{"op": "add", "path": "/extra/list/1", "value": 1.5},
+		{"op": "replace", "path": "/version", "value": 4}
+	]`), &ops)
+	if err != nil {
+		t.Fatalf("invalid test JSON: %s", err)
+	}
+
+	if err := typutil.ApplyPatch(&u, ops); err != nil {
+		t.Errorf("ApplyPatch failed: %s", err)
+		return
+	}
+	expected := User{
+		Name:    "Bob",
+		Version: 4,
+		Address: &Address{City: "Paris"},
+		Tags:    []string{"z", "a", "c"},
+		Labels:  map[string]string{"stage": "prod", "a/b": "x"},
+		Extra:   map[string]any{"list": []any{1.0, 1.5, 2.0}, "city": "Paris"},
+	}
+	if !reflect.DeepEqual(u, expected) {
+		t.Errorf("unexpected value %+v", u)
+	}
+
+	// Diff and ApplyPatch are symmetrical
+	a := map[string]any{"x": []any{"a", "b", "c"}, "y": map[string]any{"z": 1.0}}
+	b := map[string]any{"x": []any{"a"}, "w": true}
+	if err := typutil.ApplyPatch(&a, typutil.Diff(a, b)); err != nil || !reflect.DeepEqual(a, b) {
+		t.Errorf("unexpected value %v (err=%v)", a, err)
+	}
+}
+
+func TestApplyPatchAtomic(t *testing.T) {
+	type Config struct {
+		Name  string
+		Ports []int
+		Env   map[string]string
+	}
+	cfg := Config{Name: "app", Ports: []int{80}, Env: map[string]string{"A": "1"}}
+
+	tests := []typutil.PatchOp{
+		{Op: "test", Path: "/Name", Value: "other"},
+		{Op: "replace", Path: "/Missing", Value: 1},
+		{Op: "remove", Path: "/Env/C"},
+		{Op: "add", Path: "/Ports/5", Value: 1},
+		{Op: "add", Path: "/Ports/0", Value: "not a number"},
+		{Op: "move", From: "/Env", Path: "/Env/A"},
+		{Op: "frobnicate", Path: "/Name"},
+	}
+	for _, op := range tests {
+		ops := []typutil.PatchOp{
+			{Op: "replace", Path: "/Name", Value: "changed"},
+			{Op: "add", Path: "/Env/B", Value: "2"},
+			{Op: "add", Path: "/Ports/-", Value: 443},
+			op,
+		}
+		if err := typutil.ApplyPatch(&cfg, ops); err == nil {
+			t.Errorf("expected %s %s to fail", op.Op, op.Path)
+		}
+		if cfg.Name != "app" || len(cfg.Ports) != 1 || len(cfg.Env) != 1 {
+			t.Errorf("value changed after failed patch: %+v", cfg)
+		}
+	}
+
+	err := typutil.ApplyPatch(&cfg, []typutil.PatchOp{{Op: "test", Path: "/Name", Value: "x"}})
+	if !errors.Is(err, typutil.ErrPatchTestFailed) {
+		t.Errorf("expected ErrPatchTestFailed, got %v", err)
+	}
+
+	// values pointed by the destination are not modified either
+	type Inner struct {
+		X int
+	}
+	type Outer struct {
+		P *Inner
+		N int
+	}
+	o := Outer{P: &Inner{X: 1}}
+	err = typutil.ApplyPatch(&o, []typutil.PatchOp{
+		{Op: "replace", Path: "/P", Value: map[string]any{"X": 2}},
+		{Op: "test", Path: "/N", Value: 99},
+	})
+	if !errors.Is(err, typutil.ErrPatchTestFailed) {
+		t.Errorf("expected ErrPatchTestFailed, got %v", err)
+	}
+	if o.P.X != 1 {
+		t.Errorf("pointed value changed after failed patch: %+v", o.P)
+	}
+}
+
+func TestApplyPatchKeepsUntouched(t *testing.T) {
+	type Shared struct {
+		Count int
+	}
+	type Doc struct {
+		Name  string
+		Res   *Shared
+		When  time.Time
+		Meta  map[string]*Shared
+		Items []*Shared
+	}
+	loc := time.FixedZone("UTC+2", 2*3600)
+	res, a, b := &Shared{1}, &Shared{2}, &Shared{3}
+	meta := map[string]*Shared{"a": a}
+	items := []*Shared{a, b}
+	doc := Doc{Name: "x", Res: res, When: time.Date(2024, 1, 2, 3, 4, 5, 0, loc), Meta: meta, Items: items}
+
+	ops := []typutil.PatchOp{
+		{Op: "replace", Path: "/Name", Value: "y"},
+		{Op: "replace", Path: "/Items/1/Count", Value: 4},
+		{Op: "add", Path: "/Meta/b", Value: map[string]any{"Count": 5}},
+	}
+	if err := typutil.ApplyPatch(&doc, ops); err != nil {
+		t.Fatalf("ApplyPatch failed: %s", err)
+	}
+	if doc.Name != "y" || doc.Items[1].Count != 4 || doc.Meta["b"].Count != 5 {
+		t.Errorf("unexpected value %+v", doc)
+	}
+	if doc.Res != res || doc.When.Location() != loc || doc.Meta["a"] != a || doc.Items[0] != a {
+		t.Errorf("untouched pointers were copied")
+	}
+
+	// the original containers and values are not modified
+	if b.Count != 3 || items[1] != b || len(meta) != 1 {
+		t.Errorf("original value modified: %+v %v %v", b, items, meta)
+	}
+}
@@ -0,0 +1,138 @@
+package typutil
+
+import "reflect"
+
+// Merge is a functional option for NewConverter that makes conversions update
+// existing values instead of replacing them, as needed to layer configuration
+// sources:
+//
+//   - keys are added to or updated in existing maps, and values already in the
+//     map are merged with the new ones
+//   - struct fields missing from source maps are left untouched, including
+//     fields with a default tag that already have a non-zero value
+//   - struct values are merged field by field, unless they have unexported
+//     fields (such as time.Time)
+//   - non-nil pointers are reused, and the pointed value merged
+//
+// Slices are replaced, use MergeAppend to append to them instead.
+//
+// Example:
+//
+//	conv := NewConverter(Merge)
+//	err := conv.Assign(&cfg, fileValues)
+//	err = conv.Assign(&cfg, envValues) // only overrides keys present in envValues
+func Merge(c *Converter) {
+	c.merge = true
+}
+
+// MergeAppend is a functional option for NewConverter that behaves like Merge,
+// except that values converted to existing slices are appended to them.
+func MergeAppend(c *Converter) {
+	c.merge = true
+	c.mergeAppend = true
+}
+
+// merges returns true if converting srct to dstt should update the existing
+// destination value rather than replace it
+func (c *Converter) merges(dstt, srct reflect.Type) bool {
+	if !c.merge {
+		return false
+	}
+	switch dstt.Kind() {
+	case reflect.Map:
+		return srct.Kind() == reflect.Map
+	case reflect.Pointer:
+		return srct.Kind() == reflect.Pointer
+	case reflect.Interface:
+		return isGeneric(dstt) && (srct.Kind() == reflect.Map || srct.Kind() == reflect.Interface)
+	case reflect.Slice:
+		return c.mergeAppend && (srct.Kind() == reflect.Slice || srct.Kind() == reflect.Array)
+	case reflect.Struct:
+		return srct.Kind() == reflect.Struct && allExported(dstt)
+	}
+	return false
+}
+
+// allExported returns true if all the fields of the struct type t are
+// exported, so that converting field by field does not lose any state
+func allExported(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if !t.Field(i).IsExported() {
+			return false
+		}
+	}
+	return true
+}
+
+// makeAssignMerge returns an assignFunc merging src into dst, for types where
+// merges returns true
+func (c *Converter) makeAssignMerge(dstt, srct reflect.Type) (assignFunc, error) {
+	switch dstt.Kind() {
+	case reflect.Pointer:
+		return c.makeMergePtr(dstt, srct)
+	case reflect.Interface:
+		return c.makeMergeIntf(dstt, srct), nil
+	case reflect.Map:
+		return c.makeAssignToMap(dstt, srct)
+	case reflect.Struct:
+		return c.makeAssignStructToStruct(dstt, srct)
+	default:
+		return c.makeAssignToSlice(dstt, srct)
+	}
+}
+
+// makeMergePtr returns an assignFunc merging the value pointed by src into the
+// value pointed by dst, allocating it if dst is nil
+func (c *Converter) makeMergePtr(dstt, srct reflect.Type) (assignFunc, error) {
+	subt := dstt.Elem()
+	subf, err := c.getAssignFunc(subt, srct.Elem())
+	if err != nil {
+		return nil, err
+	}
+
+	f := func(dst, src reflect.Value) error {
+		if src.IsNil() {
+			dst.SetZero()
+			return nil
+		}
+		if dst.IsNil() {
+			dst.Set(reflect.New(subt))
+		}
+		return subf(dst.Elem(), src.Elem())
+	}
+	return f, nil
+}
+
+// makeMergeIntf returns an assignFunc merging maps into the map held by an
+// empty interface dst. Other values replace the value of dst.
+func (c *Converter) makeMergeIntf(dstt, srct reflect.Type) assignFunc {
+	return func(dst, src reflect.Value) error {
+		if src.Kind() == reflect.Interface {
+			src = src.Elem()
+		}
+		if !src.IsValid() {
+			dst.SetZero()
+			return nil
+		}
+		cur := dst.Elem()
+		if !cur.IsValid() || cur.Kind() != reflect.Map || src.Kind() != reflect.Map {
+			// stored as a copy, so that merging later values does not
+			// modify src
+			dst.Set(DeepCloneReflect(src))
+			return nil
+		}
+
+		// merge into a copy of the current map value, which shares its content
+		v := reflect.New(cur.Type()).Elem()
+		v.Set(cur)
+		f, err := c.getAssignFunc(v.Type(), src.Type())
+		if err != nil {
+			return err
+		}
+		if err := f(v, src); err != nil {
+			return err
+		}
+		dst.Set(v)
+		return nil
+	}
+}
@@ -0,0 +1,105 @@
+package typutil_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/KarpelesLab/typutil"
+)
+
+func TestMerge(t *testing.T) {
+	type Database struct {
+		Host string
+		Port int `default:"5432"`
+	}
+	type Config struct {
+		Name     string
+		Labels   map[string]string
+		Database *Database
+		Servers  []string
+		Extra    map[string]any
+	}
+
+	conv := typutil.NewConverter(typutil.Merge)
+
+	var cfg Config
+	layer1 := map[string]any{
+		"Name":     "app",
+		"Labels":   map[string]any{"env": "prod", "team": "core"},
+		"Database": map[string]any{"Host": "db1", "Port": 5433},
+		"Servers":  []any{"a", "b"},
+		"Extra":    map[string]any{"log": map[string]any{"level": "info", "format": "json"}},
+	}
+	err := conv.Assign(&cfg, layer1)
+	if err != nil {
+		t.Errorf("first layer failed: %s", err)
+		return
+	}
+	db := cfg.Database
+
+	err = conv.Assign(&cfg, map[string]any{
+		"Labels":   map[string]any{"env": "dev"},
+		"Database": map[string]any{"Host": "db2"},
+		"Servers":  []any{"c"},
+		"Extra":    map[string]any{"log": map[string]any{"level": "debug"}},
+	})
+	if err != nil {
+		t.Errorf("second layer failed: %s", err)
+		return
+	}
+
+	if cfg.Name != "app" {
+		t.Errorf("unexpected Name %q", cfg.Name)
+	}
+	if !reflect.DeepEqual(cfg.Labels, map[string]string{"env": "dev", "team": "core"}) {
+		t.Errorf("unexpected Labels %v", cfg.Labels)
+	}
+	if cfg.Database != db {
+		t.Errorf("expected Database pointer to be reused")
+	}
+	if cfg.Database.Host != "db2" || cfg.Database.Port != 5433 {
+		t.Errorf("unexpected Database %+v", cfg.Database)
+	}
+	if !reflect.DeepEqual(cfg.Servers, []string{"c"}) {
+		t.Errorf("unexpected Servers %v", cfg.Servers)
+	}
+	expected := map[string]any{"log": map[string]any{"level": "debug", "format": "json"}}
+	if !reflect.DeepEqual(cfg.Extra, expected) {
+		t.Errorf("unexpected Extra %v", cfg.Extra)
+	}
+
+	// merging later layers does not modify the previous ones
+	layer1Extra := map[string]any{"log": map[string]any{"level": "info", "format": "json"}}
+	if !reflect.DeepEqual(layer1["Extra"], layer1Extra) {
+		t.Errorf("first layer modified: %v", layer1["Extra"])
+	}
+
+	// without Merge, values are replaced
+	if err := typutil.Assign(&cfg, map[string]any{"Labels": map[string]any{"a": "b"}}); err != nil || len(cfg.Labels) != 1 {
+		t.Errorf("unexpected Labels %v (err=%v)", cfg.Labels, err)
+	}
+}
+
+func TestMergeAppend(t *testing.T) {
+	type Config struct {
+		Servers []string
+		Ports   []int
+	}
+
+	conv := typutil.NewConverter(typutil.MergeAppend)
+	cfg := Config{Servers: []string{"a"}, Ports: []int{80}}
+	if err := conv.Assign(&cfg, Config{Servers: []string{"b", "c"}}); err != nil {
+		t.Errorf("merge failed: %s", err)
+	}
+	if err := conv.Assign(&cfg, map[string]any{"Ports": []any{"443"}}); err != nil {
+		t.Errorf("merge failed: %s", err)
+	}
+	if !reflect.DeepEqual(cfg, Config{Servers: []string{"a", "b", "c"}, Ports: []int{80, 443}}) {
+		t.Errorf("unexpected value %+v", cfg)
+	}
+
+	m := map[string]int{"a": 1}
+	if err := conv.Assign(&m, map[string]any{"b": 2}); err != nil || !reflect.DeepEqual(m, map[string]int{"a": 1, "b": 2}) {
+		t.Errorf("unexpected value %v (err=%v)", m, err)
+	}
+}
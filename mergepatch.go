@@ -0,0 +1,258 @@
+package typutil
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// MergePatch applies patch to the value pointed by dst following RFC 7396
+// (JSON Merge Patch): objects (maps or structs) are merged recursively, null
+// values delete map keys or reset struct fields to their zero value, and any
+// other value replaces the target.
+//
+// Values are converted as with Assign, which allows a patch decoded from JSON
+// as map[string]any to update a typed struct. Struct fields are matched like
+// map keys are in Assign, and validators run on each patched struct.
+//
+// Example:
+//
+//	var patch map[string]any
+//	json.Unmarshal([]byte(`{"name":"Bob","address":{"zip":null}}`), &patch)
+//	err := typutil.MergePatch(&user, patch)
+func MergePatch(dst, patch any) error {
+	return defaultConverter.MergePatch(dst, patch)
+}
+
+// MergePatch applies patch to the value pointed by dst using the converter's
+// settings. See the package-level MergePatch for details.
+func (c *Converter) MergePatch(dst, patch any) error {
+	vdst := reflect.ValueOf(dst)
+	if vdst.Kind() != reflect.Pointer || vdst.IsNil() {
+		return ErrAssignDestNotPointer
+	}
+	return c.mergePatch(vdst.Elem(), reflect.ValueOf(patch))
+}
+
+// mergePatch applies the merge patch in patch to dst, which must be settable
+func (c *Converter) mergePatch(dst, patch reflect.Value) error {
+	for patch.Kind() == reflect.Interface || patch.Kind() == reflect.Pointer {
+		if patch.IsNil() {
+			break
+		}
+		patch = patch.Elem()
+	}
+	if isNull(patch) {
+		dst.SetZero()
+		return nil
+	}
+	if patch.Kind() == reflect.Struct {
+		// structs patches are applied as objects
+		g, err := c.toGeneric(patch)
+		if err != nil {
+			return err
+		}
+		patch = reflect.ValueOf(g)
+	}
+	if patch.Kind() != reflect.Map {
+		return c.mergePatchAssign(dst, patch)
+	}
+
+	switch dst.Kind() {
+	case reflect.Pointer:
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		return c.mergePatch(dst.Elem(), patch)
+	case reflect.Interface:
+		if !isGeneric(dst.Type()) {
+			break
+		}
+		// patch a copy of the current map, or a new object
+		var v reflect.Value
+		if cur := dst.Elem(); cur.IsValid() && cur.Kind() == reflect.Map {
+			v = reflect.New(cur.Type()).Elem()
+			v.Set(cur)
+		} else {
+			v = reflect.New(genericMapType).Elem()
+		}
+		if err := c.mergePatch(v, patch); err != nil {
+			return err
+		}
+		dst.Set(v)
+		return nil
+	case reflect.Map:
+		return c.mergePatchMap(dst, patch)
+	case reflect.Struct:
+		return c.mergePatchStruct(dst, patch)
+	}
+	return c.mergePatchAssign(dst, patch)
+}
+
+// mergePatchAssign replaces dst with the value of patch
+func (c *Converter) mergePatchAssign(dst, patch reflect.Value) error {
+	f, err := c.getAssignFunc(dst.Type(), patch.Type())
+	if err != nil {
+		return err
+	}
+	return f(dst, patch)
+}
+
+// mergePatchMap applies the object patch to the map dst
+func (c *Converter) mergePatchMap(dst, patch reflect.Value) error {
+	dstt := dst.Type()
+	kf, err := c.getAssignFunc(dstt.Key(), patch.Type().Key())
+	if err != nil {
+		return err
+	}
+	if dst.IsNil() {
+		dst.Set(reflect.MakeMap(dstt))
+	}
+
+	ec := &errorCollector{c: c}
+	iter := patch.MapRange()
+	for iter.Next() {
+		seg := fmt.Sprintf("[%v]", iter.Key())
+		k := reflect.New(dstt.Key()).Elem()
+		if err := kf(k, iter.Key()); err != nil {
+			if err := ec.add(seg, k, iter.Key(), err); err != nil {
+				return err
+			}
+			continue
+		}
+		if isNull(iter.Value()) {
+			dst.SetMapIndex(k, reflect.Value{})
+			continue
+		}
+		v := reflect.New(dstt.Elem()).Elem()
+		if cur := dst.MapIndex(k); cur.IsValid() {
+			v.Set(cur)
+		}
+		if err := c.mergePatch(v, iter.Value()); err != nil {
+			if err := ec.add(seg, v, iter.Value(), err); err != nil {
+				return err
+			}
+			continue
+		}
+		dst.SetMapIndex(k, v)
+	}
+	return ec.result()
+}
+
+// mergePatchStruct applies the object patch to the struct dst. Map keys are
+// converted with AsString.
+func (c *Converter) mergePatchStruct(dst, patch reflect.Value) error {
+	fields := make(map[string]*fieldInfo)
+	foldFields := make(map[string]*fieldInfo)
+	for _, f := range c.structFields(dst.Type()) {
+		fields[f.name] = f
+		if fold := strings.ToLower(f.name); !c.caseSensitive && foldFields[fold] == nil {
+			foldFields[fold] = f
+		}
+	}
+
+	ec := &errorCollector{c: c}
+	iter := patch.MapRange()
+	for iter.Next() {
+		key, ok := AsString(iter.Key().Interface())
+		if !ok {
+			if c.disallowUnknown {
+				ec.unknown = append(ec.unknown, fmt.Sprint(iter.Key()))
+			}
+			continue
+		}
+		f, ok := fields[key]
+		if !ok {
+			f, ok = foldFields[strings.ToLower(key)]
+			if !ok {
+				if c.disallowUnknown {
+					ec.unknown = append(ec.unknown, key)
+				}
+				continue
+			}
+			if mapHasKey(patch, f.name) {
+				// an exact match exists and takes priority
+				continue
+			}
+		}
+		dstf, err := fieldByIndexAlloc(dst, f.Index)
+		if err != nil {
+			return err
+		}
+		fc, err := c.fieldConverter(f.StructField)
+		if err == nil {
+			err = fc.mergePatch(dstf, iter.Value())
+		}
+		if err != nil {
+			if err := ec.add(key, dstf, iter.Value(), err); err != nil {
+				return err
+			}
+		}
+	}
+	if err := ec.validate(c.getValidatorForType(dst.Type()), dst); err != nil {
+		return err
+	}
+	return ec.result()
+}
+
+// CreateMergePatch returns a RFC 7396 merge patch that turns original into
+// modified when applied with MergePatch. Both values are first converted to
+// generic values (see Assign), and the patch is made of map[string]any, []any
+// and basic values.
+//
+// As merge patches cannot express it, keys whose value is null in modified
+// are removed rather than set to null.
+func CreateMergePatch(original, modified any) (any, error) {
+	return defaultConverter.CreateMergePatch(original, modified)
+}
+
+// CreateMergePatch returns a merge patch using the converter's settings. See
+// the package-level CreateMergePatch for details.
+func (c *Converter) CreateMergePatch(original, modified any) (any, error) {
+	a, err := c.toGeneric(reflect.ValueOf(original))
+	if err != nil {
+		return nil, err
+	}
+	b, err := c.toGeneric(reflect.ValueOf(modified))
+	if err != nil {
+		return nil, err
+	}
+	return mergePatchDiff(a, b), nil
+}
+
+// mergePatchDiff returns the merge patch between the generic values a and b
+func mergePatchDiff(a, b any) any {
+	am, ok := a.(map[string]any)
+	if !ok {
+		return b
+	}
+	bm, ok := b.(map[string]any)
+	if !ok {
+		return b
+	}
+
+	patch := make(map[string]any)
+	for k := range am {
+		if _, found := bm[k]; !found {
+			patch[k] = nil
+		}
+	}
+	for k, bv := range bm {
+		av, found := am[k]
+		switch {
+		case !found:
+			patch[k] = bv
+		case reflect.DeepEqual(av, bv):
+			// unchanged
+		default:
+			_, aobj := av.(map[string]any)
+			_, bobj := bv.(map[string]any)
+			if aobj && bobj {
+				patch[k] = mergePatchDiff(av, bv)
+			} else {
+				patch[k] = bv
+			}
+		}
+	}
+	return patch
+}
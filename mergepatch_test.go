@@ -0,0 +1,105 @@
+package typutil_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/KarpelesLab/typutil"
+)
+
+func TestMergePatch(t *testing.T) {
+	type Address struct {
+		City string `json:"city"`
+		Zip  string `json:"zip"`
+	}
+	type User struct {
+		Name    string            `json:"name"`
+		Age     int               `json:"age"`
+		Address *Address          `json:"address"`
+		Tags    []string          `json:"tags"`
+		Labels  map[string]string `json:"labels"`
+		Extra   map[string]any    `json:"extra"`
+	}
+
+	u := User{
+		Name:    "alice",
+		Age:     30,
+		Address: &Address{City: "Paris", Zip: "75001"},
+		Tags:    []string{"a", "b"},
+		Labels:  map[string]string{"env": "prod", "team": "core"},
+		Extra:   map[string]any{"x": map[string]any{"y": 1.0, "z": 2.0}},
+	}
+
+	var patch map[string]any
+	err := json.Unmarshal([]byte(`{
+		"name": "Bob",
+		"age": "31",
+		"address": {"zip": null},
+		"tags": ["c"],
+		"labels": {"team": null, "tier": "gold"},
+		"extra": {"x": {"z": null}, "n": {"a": 1, "b": null}}
+	}`), &patch)
+	if err != nil {
+		t.Fatalf("invalid test JSON: %s", err)
+	}
+
+	if err := typutil.MergePatch(&u, patch); err != nil {
+		t.Errorf("MergePatch failed: %s", err)
+		return
+	}
+	expected := User{
+		Name:    "Bob",
+		Age:     31,
+		Address: &Address{City: "Paris"},
+		Tags:    []string{"c"},
+		Labels:  map[string]string{"env": "prod", "tier": "gold"},
+		Extra:   map[string]any{"x": map[string]any{"y": 1.0}, "n": map[string]any{"a": 1.0}},
+	}
+	if !reflect.DeepEqual(u, expected) {
+		t.Errorf("unexpected value %+v", u)
+	}
+
+	// null resets struct fields
+	if err := typutil.MergePatch(&u, map[string]any{"address": nil, "age": nil}); err != nil || u.Address != nil || u.Age != 0 {
+		t.Errorf("unexpected value %+v (err=%v)", u, err)
+	}
+
+	// invalid values report the path of the field
+	err = typutil.MergePatch(&u, map[string]any{"address": map[string]any{"city": map[string]any{"a": 1}}})
+	if err == nil || err.Error()[:12] != "address.city" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestCreateMergePatch(t *testing.T) {
+	type Address struct {
+		City string `json:"city"`
+		Zip  string `json:"zip,omitempty"`
+	}
+	type User struct {
+		Name    string   `json:"name"`
+		Address Address  `json:"address"`
+		Tags    []string `json:"tags,omitempty"`
+	}
+
+	a := User{Name: "alice", Address: Address{City: "Paris", Zip: "75001"}, Tags: []string{"x"}}
+	b := User{Name: "alice", Address: Address{City: "Lyon"}}
+
+	patch, err := typutil.CreateMergePatch(a, b)
+	if err != nil {
+		t.Errorf("CreateMergePatch failed: %s", err)
+		return
+	}
+	expected := map[string]any{
+		"address": map[string]any{"city": "Lyon", "zip": nil},
+		"tags":    nil,
+	}
+	if !reflect.DeepEqual(patch, expected) {
+		t.Errorf("unexpected patch %#v", patch)
+	}
+
+	if err := typutil.MergePatch(&a, patch); err != nil || !reflect.DeepEqual(a, b) {
+		t.Errorf("patched value %+v does not match %+v (err=%v)", a, b, err)
+	}
+}
@@ -0,0 +1,92 @@
+package typutil
+
+import (
+	"strings"
+	"unicode"
+)
+
+// NamePolicy returns the name used to match a struct field that is not named
+// by a struct tag, given the Go name of the field.
+//
+// SnakeCase, KebabCase and CamelCase are provided, and any function with the
+// same signature can be used.
+type NamePolicy func(fieldName string) string
+
+// FieldNames is a functional option for NewConverter that sets the policy
+// used to name struct fields without a name in their tag, both when matching
+// map keys and when converting structs to maps.
+//
+// Example:
+//
+//	conv := NewConverter(FieldNames(SnakeCase))
+//	// map[string]any{"user_name": "alice"} now sets the UserName field
+func FieldNames(policy NamePolicy) converterOption {
+	return func(c *Converter) {
+		c.namePolicy = policy
+	}
+}
+
+// CaseSensitive is a functional option for NewConverter that disables case
+// insensitive matching of map keys with struct fields. By default, like
+// encoding/json, a key matches a field when it is equal to its name ignoring
+// case, with exact matches taking priority.
+func CaseSensitive(c *Converter) {
+	c.caseSensitive = true
+}
+
+// SnakeCase converts a Go name such as "UserID" to snake case ("user_id").
+func SnakeCase(name string) string {
+	return strings.ToLower(strings.Join(splitWords(name), "_"))
+}
+
+// KebabCase converts a Go name such as "UserID" to kebab case ("user-id").
+func KebabCase(name string) string {
+	return strings.ToLower(strings.Join(splitWords(name), "-"))
+}
+
+// CamelCase converts a Go name such as "UserID" to lower camel case
+// ("userID"), as commonly used in JavaScript.
+func CamelCase(name string) string {
+	words := splitWords(name)
+	if len(words) == 0 {
+		return name
+	}
+	words[0] = strings.ToLower(words[0])
+	return strings.Join(words, "")
+}
+
+// splitWords splits a Go name into words, keeping acronyms together:
+// "HTTPServerID" gives "HTTP", "Server" and "ID".
+func splitWords(name string) []string {
+	var words []string
+	runes := []rune(name)
+	start := 0
+
+	for i := 1; i < len(runes); i++ {
+		prev, cur := runes[i-1], runes[i]
+		switch {
+		case cur == '_' || cur == '-':
+			if start < i {
+				words = append(words, string(runes[start:i]))
+			}
+			start = i + 1
+			continue
+		case !unicode.IsUpper(cur):
+			continue
+		case unicode.IsLower(prev) || unicode.IsDigit(prev):
+			// "userID" → "user", "ID"
+		case unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1]):
+			// "HTTPServer" → "HTTP", "Server"
+		default:
+			continue
+		}
+		if start < i {
+			words = append(words, string(runes[start:i]))
+		}
+		start = i
+	}
+	if start < len(runes) {
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
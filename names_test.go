@@ -0,0 +1,92 @@
+package typutil_test
+
+import (
+	"testing"
+
+	"github.com/KarpelesLab/typutil"
+)
+
+func TestCaseInsensitiveKeys(t *testing.T) {
+	type User struct {
+		UserName string `json:"username"`
+		Email    string
+		ID       int
+	}
+
+	u, err := typutil.As[User](map[string]any{"userName": "alice", "EMAIL": "a@example.com", "id": 1})
+	if err != nil {
+		t.Errorf("map to struct failed: %s", err)
+	} else if u.UserName != "alice" || u.Email != "a@example.com" || u.ID != 1 {
+		t.Errorf("unexpected value %+v", u)
+	}
+
+	// exact matches take priority regardless of map order
+	for i := 0; i < 10; i++ {
+		u, err = typutil.As[User](map[string]any{"id": 1, "ID": 2, "Id": 3})
+		if err != nil || u.ID != 2 {
+			t.Errorf("expected exact match to win, got %d (err=%v)", u.ID, err)
+			break
+		}
+	}
+
+	conv := typutil.NewConverter(typutil.CaseSensitive)
+	u, err = typutil.AsWith[User](conv, map[string]any{"userName": "alice", "Email": "a@example.com"})
+	if err != nil || u.UserName != "" || u.Email != "a@example.com" {
+		t.Errorf("unexpected value %+v (err=%v)", u, err)
+	}
+}
+
+func TestNamePolicies(t *testing.T) {
+	tests := []struct {
+		name                string
+		snake, kebab, camel string
+	}{
+		{"UserID", "user_id", "user-id", "userID"},
+		{"HTTPServer", "http_server", "http-server", "httpServer"},
+		{"Name", "name", "name", "name"},
+		{"ID", "id", "id", "id"},
+		{"Field2Name", "field2_name", "field2-name", "field2Name"},
+		{"Already_Snake", "already_snake", "already-snake", "alreadySnake"},
+	}
+
+	for _, tt := range tests {
+		if s := typutil.SnakeCase(tt.name); s != tt.snake {
+			t.Errorf("SnakeCase(%q) = %q, expected %q", tt.name, s, tt.snake)
+		}
+		if s := typutil.KebabCase(tt.name); s != tt.kebab {
+			t.Errorf("KebabCase(%q) = %q, expected %q", tt.name, s, tt.kebab)
+		}
+		if s := typutil.CamelCase(tt.name); s != tt.camel {
+			t.Errorf("CamelCase(%q) = %q, expected %q", tt.name, s, tt.camel)
+		}
+	}
+}
+
+func TestFieldNames(t *testing.T) {
+	type Config struct {
+		ListenAddr string
+		MaxConns   int
+		Debug      bool `json:"verbose"`
+	}
+
+	snake := typutil.NewConverter(typutil.FieldNames(typutil.SnakeCase))
+	c, err := typutil.AsWith[Config](snake, map[string]any{"listen_addr": ":80", "MAX_CONNS": 10, "verbose": true})
+	if err != nil {
+		t.Errorf("map to struct failed: %s", err)
+	} else if c.ListenAddr != ":80" || c.MaxConns != 10 || !c.Debug {
+		t.Errorf("unexpected value %+v", c)
+	}
+
+	var m map[string]any
+	if err := snake.Assign(&m, c); err != nil {
+		t.Errorf("struct to map failed: %s", err)
+	} else if m["listen_addr"] != ":80" || m["max_conns"] != 10 || m["verbose"] != true {
+		t.Errorf("unexpected map %v", m)
+	}
+
+	kebab := typutil.NewConverter(typutil.FieldNames(typutil.KebabCase))
+	c, err = typutil.AsWith[Config](kebab, map[string]any{"listen-addr": ":81"})
+	if err != nil || c.ListenAddr != ":81" {
+		t.Errorf("unexpected value %+v (err=%v)", c, err)
+	}
+}
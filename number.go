@@ -0,0 +1,182 @@
+package typutil
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// LossyNumbers is a functional option for NewConverter that disables range and
+// precision checks when assigning numbers.
+//
+// By default, assigning a value that does not fit in the destination type (such
+// as 300 into an int8 or -1 into an uint) returns an error wrapping ErrOverflow,
+// and assigning a value that cannot be represented exactly (such as 3.7 into an
+// int) returns an error wrapping ErrPrecisionLoss. With LossyNumbers, values wrap
+// around like a Go conversion would, and floats are rounded to the nearest
+// integer.
+func LossyNumbers(c *Converter) {
+	c.lossyNum = true
+}
+
+// numberOf returns the value of src as an int64, uint64 or float64
+func (c *Converter) numberOf(src reflect.Value) (any, bool) {
+	switch src.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return src.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return src.Uint(), true
+	case reflect.Float32, reflect.Float64:
+		return src.Float(), true
+	}
+	return c.asNumber(src.Interface())
+}
+
+// asNumber converts v to an int64, uint64 or float64 according to the converter's number policy
+func (c *Converter) asNumber(v any) (any, bool) {
+	v = BaseType(v)
+	if b, ok := v.([]byte); ok {
+		v = string(b)
+	}
+	if !c.strictNum {
+		return AsNumber(v)
+	}
+
+	switch s := v.(type) {
+	case bool:
+		return nil, false
+	case string:
+		if res, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return res, true
+		}
+		if res, err := strconv.ParseUint(s, 10, 64); err == nil {
+			return res, true
+		}
+		// ParseFloat also accepts hexadecimal floats such as 0x1p-2 and _ separators
+		if strings.ContainsAny(s, "xX_") {
+			return nil, false
+		}
+		if res, err := strconv.ParseFloat(s, 64); err == nil {
+			return res, true
+		}
+		return nil, false
+	}
+	return AsNumber(v)
+}
+
+func overflowError(n any, t reflect.Type) error {
+	return fmt.Errorf("%w: %v does not fit in %s", ErrOverflow, n, t)
+}
+
+func precisionError(n any, t reflect.Type) error {
+	return fmt.Errorf("%w: %v cannot be represented exactly as %s", ErrPrecisionLoss, n, t)
+}
+
+// setInt sets dst (of a signed integer kind) to n, which is an int64, uint64 or float64
+func (c *Converter) setInt(dst reflect.Value, n any) error {
+	var i int64
+	switch x := n.(type) {
+	case int64:
+		i = x
+	case uint64:
+		if x > math.MaxInt64 && !c.lossyNum {
+			return overflowError(n, dst.Type())
+		}
+		i = int64(x)
+	case float64:
+		r := math.Round(x)
+		if math.IsNaN(x) || r < -0x1p63 || r >= 0x1p63 {
+			// cannot be converted at all, even in lossy mode
+			return overflowError(n, dst.Type())
+		}
+		if r != x && !c.lossyNum {
+			return precisionError(n, dst.Type())
+		}
+		i = int64(r)
+	default:
+		return fmt.Errorf("%w: cannot convert %T to int", ErrAssignImpossible, n)
+	}
+
+	if dst.OverflowInt(i) && !c.lossyNum {
+		return overflowError(n, dst.Type())
+	}
+	dst.SetInt(i)
+	return nil
+}
+
+// setUint sets dst (of an unsigned integer kind) to n, which is an int64, uint64 or float64
+func (c *Converter) setUint(dst reflect.Value, n any) error {
+	var u uint64
+	switch x := n.(type) {
+	case int64:
+		if x < 0 && !c.lossyNum {
+			return overflowError(n, dst.Type())
+		}
+		u = uint64(x)
+	case uint64:
+		u = x
+	case float64:
+		r := math.Round(x)
+		if math.IsNaN(x) || r < 0 || r >= 0x1p64 {
+			// cannot be converted at all, even in lossy mode
+			return overflowError(n, dst.Type())
+		}
+		if r != x && !c.lossyNum {
+			return precisionError(n, dst.Type())
+		}
+		u = uint64(r)
+	default:
+		return fmt.Errorf("%w: cannot convert %T to uint", ErrAssignImpossible, n)
+	}
+
+	if dst.OverflowUint(u) && !c.lossyNum {
+		return overflowError(n, dst.Type())
+	}
+	dst.SetUint(u)
+	return nil
+}
+
+// setFloat sets dst (of a float kind) to n, which is an int64, uint64 or float64
+func (c *Converter) setFloat(dst reflect.Value, n any) error {
+	is32 := dst.Kind() == reflect.Float32
+
+	var f float64
+	switch x := n.(type) {
+	case int64:
+		if is32 {
+			f = float64(float32(x))
+		} else {
+			f = float64(x)
+		}
+		if (f >= 0x1p63 || int64(f) != x) && !c.lossyNum {
+			return precisionError(n, dst.Type())
+		}
+	case uint64:
+		if is32 {
+			f = float64(float32(x))
+		} else {
+			f = float64(x)
+		}
+		if (f >= 0x1p64 || uint64(f) != x) && !c.lossyNum {
+			return precisionError(n, dst.Type())
+		}
+	case float64:
+		f = x
+		if is32 && !c.lossyNum {
+			if dst.OverflowFloat(f) {
+				return overflowError(n, dst.Type())
+			}
+			if f != 0 && float32(f) == 0 {
+				// value too small to be represented, would become zero
+				return precisionError(n, dst.Type())
+			}
+		}
+	default:
+		return fmt.Errorf("%w: cannot convert %T to float", ErrAssignImpossible, n)
+	}
+
+	dst.SetFloat(f)
+	return nil
+}
@@ -0,0 +1,130 @@
+package typutil_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/KarpelesLab/typutil"
+)
+
+func TestAssignNumberOverflow(t *testing.T) {
+	tests := []struct {
+		name string
+		dst  any
+		src  any
+	}{
+		{"int8 from int", new(int8), 300},
+		{"int8 from negative int", new(int8), -129},
+		{"int16 from string", new(int16), "40000"},
+		{"int32 from int64", new(int32), int64(math.MaxInt32 + 1)},
+		{"int64 from uint64", new(int64), uint64(math.MaxUint64)},
+		{"int from float", new(int), 1e30},
+		{"uint from negative int", new(uint), -1},
+		{"uint8 from int", new(uint8), 256},
+		{"uint16 from json.Number", new(uint16), json.Number("65536")},
+		{"uint32 from float", new(uint32), float64(math.MaxUint32 + 1)},
+		{"uint64 from negative float", new(uint64), -1.0},
+		{"float32 from float64", new(float32), 1e300},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := typutil.Assign(tt.dst, tt.src)
+			if !errors.Is(err, typutil.ErrOverflow) {
+				t.Errorf("expected ErrOverflow, got %v", err)
+			}
+		})
+	}
+}
+
+func TestAssignNumberPrecision(t *testing.T) {
+	tests := []struct {
+		name string
+		dst  any
+		src  any
+	}{
+		{"int from float", new(int), 3.7},
+		{"int8 from float32", new(int8), float32(1.5)},
+		{"uint from string", new(uint), "2.5"},
+		{"float64 from large int", new(float64), int64(1<<53 + 1)},
+		{"float32 from int", new(float32), 16777217},
+		{"float32 from uint64", new(float32), uint64(math.MaxUint64)},
+		{"float32 underflow", new(float32), 1e-50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := typutil.Assign(tt.dst, tt.src)
+			if !errors.Is(err, typutil.ErrPrecisionLoss) {
+				t.Errorf("expected ErrPrecisionLoss, got %v", err)
+			}
+		})
+	}
+}
+
+func TestAssignNumberInRange(t *testing.T) {
+	var i8 int8
+	if err := typutil.Assign(&i8, 127); err != nil || i8 != 127 {
+		t.Errorf("expected 127, got %d (err=%v)", i8, err)
+	}
+	if err := typutil.Assign(&i8, int64(-128)); err != nil || i8 != -128 {
+		t.Errorf("expected -128, got %d (err=%v)", i8, err)
+	}
+
+	var i int
+	if err := typutil.Assign(&i, int8(-5)); err != nil || i != -5 {
+		t.Errorf("expected -5, got %d (err=%v)", i, err)
+	}
+	if err := typutil.Assign(&i, 42.0); err != nil || i != 42 {
+		t.Errorf("expected 42, got %d (err=%v)", i, err)
+	}
+
+	var u16 uint16
+	if err := typutil.Assign(&u16, uint8(200)); err != nil || u16 != 200 {
+		t.Errorf("expected 200, got %d (err=%v)", u16, err)
+	}
+
+	var f32 float32
+	if err := typutil.Assign(&f32, 0.1); err != nil || f32 != 0.1 {
+		t.Errorf("expected 0.1, got %v (err=%v)", f32, err)
+	}
+	if err := typutil.Assign(&f32, 16777216); err != nil || f32 != 16777216 {
+		t.Errorf("expected 16777216, got %v (err=%v)", f32, err)
+	}
+
+	var f64 float64
+	if err := typutil.Assign(&f64, float32(1.5)); err != nil || f64 != 1.5 {
+		t.Errorf("expected 1.5, got %v (err=%v)", f64, err)
+	}
+}
+
+func TestAssignNumberLossy(t *testing.T) {
+	conv := typutil.NewConverter(typutil.LossyNumbers)
+
+	var i8 int8
+	if err := conv.Assign(&i8, 300); err != nil || i8 != 44 {
+		t.Errorf("expected 44, got %d (err=%v)", i8, err)
+	}
+
+	var i int
+	if err := conv.Assign(&i, 3.7); err != nil || i != 4 {
+		t.Errorf("expected 4, got %d (err=%v)", i, err)
+	}
+
+	var u uint8
+	if err := conv.Assign(&u, -1); err != nil || u != 255 {
+		t.Errorf("expected 255, got %d (err=%v)", u, err)
+	}
+
+	var f32 float32
+	if err := conv.Assign(&f32, 16777217); err != nil || f32 != 16777216 {
+		t.Errorf("expected 16777216, got %v (err=%v)", f32, err)
+	}
+
+	// NaN cannot be converted to an integer, even in lossy mode
+	if err := conv.Assign(&i, math.NaN()); !errors.Is(err, typutil.ErrOverflow) {
+		t.Errorf("expected ErrOverflow for NaN, got %v", err)
+	}
+}
@@ -0,0 +1,202 @@
+package typutil
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// polyType describes the concrete types registered for an interface type,
+// selected by the value of a discriminator field
+type polyType struct {
+	field string                  // discriminator key, such as "type"
+	types map[string]reflect.Type // concrete type by discriminator value
+}
+
+// polyRegistry holds the polymorphic interface types registered with
+// RegisterPolymorphic. A registry with a parent falls back to it for interface
+// types it does not define itself.
+type polyRegistry struct {
+	parent *polyRegistry
+	m      map[reflect.Type]*polyType  // by interface type
+	names  map[reflect.Type][]polyName // discriminators by concrete type
+	lk     sync.RWMutex
+}
+
+// polyName is a discriminator field and value identifying a concrete type
+type polyName struct {
+	field, name string
+}
+
+// globalPolymorphs is the registry used by RegisterPolymorphic
+var globalPolymorphs = newPolyRegistry(nil)
+
+func newPolyRegistry(parent *polyRegistry) *polyRegistry {
+	return &polyRegistry{parent: parent, m: make(map[reflect.Type]*polyType), names: make(map[reflect.Type][]polyName)}
+}
+
+func (r *polyRegistry) set(intf, typ reflect.Type, field, name string) {
+	if intf.Kind() != reflect.Interface {
+		panic(fmt.Sprintf("polymorphic type %s is not an interface", intf))
+	}
+	if !typ.Implements(intf) {
+		panic(fmt.Sprintf("type %s does not implement %s", typ, intf))
+	}
+
+	r.lk.Lock()
+	defer r.lk.Unlock()
+
+	p, ok := r.m[intf]
+	if !ok {
+		p = &polyType{field: field, types: make(map[string]reflect.Type)}
+		r.m[intf] = p
+	} else if p.field != field {
+		panic(fmt.Sprintf("interface %s already uses discriminator field %q", intf, p.field))
+	}
+	p.types[name] = typ
+	r.names[typ] = append(r.names[typ], polyName{field, name})
+	converterGeneration.Add(1)
+}
+
+func (r *polyRegistry) get(intf reflect.Type) *polyType {
+	r.lk.RLock()
+	p, ok := r.m[intf]
+	r.lk.RUnlock()
+
+	if !ok && r.parent != nil {
+		return r.parent.get(intf)
+	}
+	return p
+}
+
+// namesOf returns the discriminators identifying the concrete type typ
+func (r *polyRegistry) namesOf(typ reflect.Type) []polyName {
+	r.lk.RLock()
+	res := r.names[typ]
+	r.lk.RUnlock()
+
+	if r.parent != nil {
+		res = append(res[:len(res):len(res)], r.parent.namesOf(typ)...)
+	}
+	return res
+}
+
+// RegisterPolymorphic registers T as the concrete type to use when assigning a
+// map to the interface type I and the map's discriminator field has the value
+// name. All the types registered for a given interface must use the same
+// field. Registration panics if I is not an interface or T does not
+// implement it.
+//
+// When converting a value of type T to a map, the discriminator field is set
+// to name unless a struct field already provides it.
+//
+// Example:
+//
+//	typutil.RegisterPolymorphic[Shape, *Circle]("type", "circle")
+//	typutil.RegisterPolymorphic[Shape, *Square]("type", "square")
+//
+//	var s Shape
+//	err := typutil.Assign(&s, map[string]any{"type": "circle", "radius": 2})
+//	// s is a *Circle
+func RegisterPolymorphic[I, T any](field, name string) {
+	RegisterPolymorphicWith[I, T](defaultConverter, field, name)
+}
+
+// RegisterPolymorphicWith registers T as a concrete type of the interface I
+// with the given Converter. When c is not the default converter, the
+// registration is only used by c. See RegisterPolymorphic for details.
+func RegisterPolymorphicWith[I, T any](c *Converter, field, name string) {
+	c.polymorphs.set(reflect.TypeFor[I](), reflect.TypeFor[T](), field, name)
+}
+
+// makeAssignPoly returns an assignFunc instantiating the concrete type of the
+// interface dstt matching the discriminator of src, or nil if dstt is not a
+// registered interface type or srct cannot hold a discriminator
+func (c *Converter) makeAssignPoly(dstt, srct reflect.Type) assignFunc {
+	if dstt.Kind() != reflect.Interface || (srct.Kind() != reflect.Map && srct.Kind() != reflect.Interface) {
+		return nil
+	}
+	p := c.polymorphs.get(dstt)
+	if p == nil {
+		return nil
+	}
+
+	return func(dst, src reflect.Value) error {
+		if src.Kind() == reflect.Interface {
+			if src.IsNil() {
+				dst.SetZero()
+				return nil
+			}
+			src = src.Elem()
+		}
+		if src.Type().AssignableTo(dstt) {
+			dst.Set(src)
+			return nil
+		}
+		if src.Kind() != reflect.Map {
+			return cannotConvert(src, dstt)
+		}
+
+		dv, ok := mapLookup(src, p.field)
+		if !ok {
+			return fmt.Errorf("%w: missing %q field to select the type of %s", ErrAssignImpossible, p.field, dstt)
+		}
+		name, _ := AsString(dv.Interface())
+		typ, ok := p.types[name]
+		if !ok {
+			return fmt.Errorf("%w: unknown %s %q for %s", ErrAssignImpossible, p.field, name, dstt)
+		}
+
+		if c.disallowUnknown && !c.hasField(typ, p.field) {
+			// the discriminator is not an unknown field
+			src = mapWithout(src, p.field)
+		}
+		f, err := c.getAssignFunc(typ, src.Type())
+		if err != nil {
+			return err
+		}
+		v := reflect.New(typ).Elem()
+		if err := f(v, src); err != nil {
+			return err
+		}
+		dst.Set(v)
+		return nil
+	}
+}
+
+// polyNames returns the discriminators to add when converting the struct type
+// t to a map
+func (c *Converter) polyNames(t reflect.Type) []polyName {
+	return slices.Concat(c.polymorphs.namesOf(t), c.polymorphs.namesOf(reflect.PointerTo(t)))
+}
+
+// hasField returns true if the struct (or pointer to struct) type t has a
+// field matching the given name
+func (c *Converter) hasField(t reflect.Type, name string) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for _, f := range c.structFields(t) {
+		if f.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// mapWithout returns a copy of m without the key equal to name
+func mapWithout(m reflect.Value, name string) reflect.Value {
+	res := reflect.MakeMapWithSize(m.Type(), m.Len())
+	iter := m.MapRange()
+	for iter.Next() {
+		if k, ok := AsString(iter.Key().Interface()); ok && k == name {
+			continue
+		}
+		res.SetMapIndex(iter.Key(), iter.Value())
+	}
+	return res
+}
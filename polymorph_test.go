@@ -0,0 +1,90 @@
+package typutil_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/KarpelesLab/typutil"
+)
+
+type testShape interface {
+	Area() float64
+}
+
+type testCircle struct {
+	Radius float64 `json:"radius"`
+}
+
+func (c *testCircle) Area() float64 { return math.Pi * c.Radius * c.Radius }
+
+type testRect struct {
+	Kind   string  `json:"kind"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+func (r testRect) Area() float64 { return r.Width * r.Height }
+
+func TestPolymorphic(t *testing.T) {
+	conv := typutil.NewConverter(typutil.DisallowUnknownFields)
+	typutil.RegisterPolymorphicWith[testShape, *testCircle](conv, "kind", "circle")
+	typutil.RegisterPolymorphicWith[testShape, testRect](conv, "kind", "rect")
+
+	type Drawing struct {
+		Main   testShape   `json:"main"`
+		Shapes []testShape `json:"shapes"`
+		None   testShape   `json:"none"`
+	}
+
+	d, err := typutil.AsWith[Drawing](conv, map[string]any{
+		"main": map[string]any{"kind": "circle", "radius": 1},
+		"shapes": []any{
+			map[string]any{"kind": "rect", "width": "2", "height": 3},
+			map[any]any{"kind": "circle", "radius": 2},
+		},
+		"none": nil,
+	})
+	if err != nil {
+		t.Errorf("map to struct failed: %s", err)
+		return
+	}
+	expected := Drawing{
+		Main:   &testCircle{Radius: 1},
+		Shapes: []testShape{testRect{Kind: "rect", Width: 2, Height: 3}, &testCircle{Radius: 2}},
+	}
+	if !reflect.DeepEqual(d, expected) {
+		t.Errorf("unexpected value %+v", d)
+	}
+
+	// the discriminator is injected when converting back to maps
+	m, err := typutil.AsWith[map[string]any](conv, d)
+	if err != nil {
+		t.Errorf("struct to map failed: %s", err)
+		return
+	}
+	expectedMap := map[string]any{
+		"main": map[string]any{"kind": "circle", "radius": 1.0},
+		"shapes": []any{
+			map[string]any{"kind": "rect", "width": 2.0, "height": 3.0},
+			map[string]any{"kind": "circle", "radius": 2.0},
+		},
+		"none": nil,
+	}
+	if !reflect.DeepEqual(m, expectedMap) {
+		t.Errorf("unexpected value %#v", m)
+	}
+
+	var s testShape
+	if err := conv.Assign(&s, map[string]any{"kind": "triangle"}); err == nil {
+		t.Errorf("expected error for unknown kind")
+	}
+	if err := conv.Assign(&s, map[string]any{"radius": 1}); err == nil {
+		t.Errorf("expected error for missing kind")
+	}
+
+	// registrations are specific to the converter
+	if err := typutil.Assign(&s, map[string]any{"kind": "circle"}); err == nil {
+		t.Errorf("expected error with the default converter")
+	}
+}
@@ -0,0 +1,74 @@
+package typutil
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+)
+
+var fieldSetType = reflect.TypeFor[FieldSet]()
+
+// FieldSet holds the names of the struct fields that were present in the
+// source map of a conversion.
+//
+// When a struct has a field of type FieldSet, converting a map to that struct
+// fills it with the Go names of the fields whose key was present in the map,
+// even when the value was the zero value or nil. This allows telling missing
+// keys from zero values, for example when handling PATCH requests. The field
+// itself never takes part in conversions.
+//
+// Example:
+//
+//	type UserPatch struct {
+//		Name    string
+//		Admin   bool
+//		Present typutil.FieldSet
+//	}
+//
+//	p, err := typutil.As[UserPatch](map[string]any{"Admin": false})
+//	p.Present.Has("Admin") // true
+//	p.Present.Has("Name")  // false
+type FieldSet map[string]struct{}
+
+// Has returns true if the field with the given Go name was present.
+func (s FieldSet) Has(name string) bool {
+	_, ok := s[name]
+	return ok
+}
+
+// Names returns the Go names of the fields that were present, sorted.
+func (s FieldSet) Names() []string {
+	res := make([]string, 0, len(s))
+	for name := range s {
+		res = append(res, name)
+	}
+	slices.Sort(res)
+	return res
+}
+
+// hasTagOption returns true if one of the converter's tags of f has the given
+// option, such as "required" in `json:"email,required"`
+func (c *Converter) hasTagOption(f reflect.StructField, opt string) bool {
+	for _, tag := range c.fieldTags(f) {
+		_, opts, _ := strings.Cut(tag, ",")
+		for opts != "" {
+			var o string
+			o, opts, _ = strings.Cut(opts, ",")
+			if o == opt {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// fieldSetIndex returns the index of the first field of type FieldSet of the
+// struct type t, or nil if there is none
+func fieldSetIndex(t reflect.Type) []int {
+	for i := 0; i < t.NumField(); i++ {
+		if f := t.Field(i); f.Type == fieldSetType && f.IsExported() {
+			return f.Index
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package typutil_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/KarpelesLab/typutil"
+)
+
+func TestRequiredFields(t *testing.T) {
+	type Signup struct {
+		Email  string `json:"email,required"`
+		Terms  bool   `json:"terms,required"`
+		Invite string `json:"invite"`
+	}
+
+	s, err := typutil.As[Signup](map[string]any{"email": "a@example.com", "terms": false})
+	if err != nil {
+		t.Errorf("zero values should satisfy required, got %s", err)
+	} else if s.Email != "a@example.com" || s.Terms {
+		t.Errorf("unexpected value %+v", s)
+	}
+
+	_, err = typutil.As[Signup](map[string]any{"email": "a@example.com"})
+	var cerr *typutil.ConversionError
+	if !errors.Is(err, typutil.ErrMissingField) || !errors.As(err, &cerr) {
+		t.Errorf("expected ErrMissingField, got %v", err)
+	} else if cerr.Path != "terms" || cerr.Reason != typutil.ReasonMissing {
+		t.Errorf("unexpected error path=%q reason=%s", cerr.Path, cerr.Reason)
+	}
+
+	// with CollectErrors, every missing field is reported
+	conv := typutil.NewConverter(typutil.CollectErrors)
+	_, err = typutil.AsWith[Signup](conv, map[string]any{"invite": "x"})
+	errs, ok := err.(typutil.MultiError)
+	if !ok || len(errs) != 2 {
+		t.Errorf("expected 2 errors, got %v", err)
+	}
+}
+
+func TestFieldSet(t *testing.T) {
+	type Address struct {
+		City    string
+		Zip     string
+		Present typutil.FieldSet
+	}
+	type UserPatch struct {
+		Name    *string
+		Admin   bool `json:"admin"`
+		Age     int
+		Address *Address
+		Present typutil.FieldSet
+	}
+
+	p, err := typutil.As[UserPatch](map[string]any{
+		"admin":   false,
+		"Name":    nil,
+		"Address": map[string]any{"zip": ""},
+	})
+	if err != nil {
+		t.Errorf("map to struct failed: %s", err)
+		return
+	}
+	if names := p.Present.Names(); !slices.Equal(names, []string{"Address", "Admin", "Name"}) {
+		t.Errorf("unexpected present fields %v", names)
+	}
+	if p.Present.Has("Age") {
+		t.Errorf("Age should not be present")
+	}
+	if p.Address == nil || !p.Address.Present.Has("Zip") || p.Address.Present.Has("City") {
+		t.Errorf("unexpected nested present fields %v", p.Address)
+	}
+
+	// the FieldSet field never takes part in conversions
+	var m map[string]any
+	if err := typutil.Assign(&m, p); err != nil {
+		t.Errorf("struct to map failed: %s", err)
+	} else if _, ok := m["Present"]; ok {
+		t.Errorf("FieldSet should not be converted, got %v", m)
+	}
+}
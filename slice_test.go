@@ -39,7 +39,7 @@ func TestSliceToSlice(t *testing.T) {
 	// Test float slice to int slice
 	floatSlice := []float64{1.5, 2.5, 3.5}
 	var intSlice2 []int
-	err = typutil.Assign(&intSlice2, floatSlice)
+	err = typutil.NewConverter(typutil.LossyNumbers).Assign(&intSlice2, floatSlice)
 	if err != nil {
 		t.Errorf("float slice to int slice failed: %s", err)
 	}
@@ -0,0 +1,64 @@
+package typutil_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/KarpelesLab/typutil"
+)
+
+func TestTagNames(t *testing.T) {
+	type Row struct {
+		ID    int    `db:"row_id" json:"id"`
+		Name  string `json:"name"`
+		Email string `db:",required" json:"email"`
+		Note  string `db:"-" json:"note"`
+	}
+
+	conv := typutil.NewConverter(typutil.TagNames("db", "json"))
+	r, err := typutil.AsWith[Row](conv, map[string]any{"row_id": 1, "id": 2, "name": "alice", "email": "a@example.com", "note": "x"})
+	if err != nil {
+		t.Errorf("map to struct failed: %s", err)
+	} else if r.ID != 1 || r.Name != "alice" || r.Email != "a@example.com" || r.Note != "" {
+		t.Errorf("unexpected value %+v", r)
+	}
+
+	// the required option of the db tag applies
+	if _, err := typutil.AsWith[Row](conv, map[string]any{"row_id": 1}); err == nil {
+		t.Errorf("expected missing field error")
+	}
+
+	// the default converter only looks at the json tag
+	r, err = typutil.As[Row](map[string]any{"row_id": 1, "id": 2, "note": "x"})
+	if err != nil || r.ID != 2 || r.Note != "x" {
+		t.Errorf("unexpected value %+v (err=%v)", r, err)
+	}
+}
+
+func TestTyputilTag(t *testing.T) {
+	type Account struct {
+		ID       int    `json:"id" typutil:"account_id"`
+		Password string `json:"password" typutil:"-"`
+		Internal string `json:"-" typutil:"internal"`
+	}
+
+	a, err := typutil.As[Account](map[string]any{"account_id": 7, "password": "secret", "internal": "yes"})
+	if err != nil {
+		t.Errorf("map to struct failed: %s", err)
+	} else if a.ID != 7 || a.Password != "" || a.Internal != "yes" {
+		t.Errorf("unexpected value %+v", a)
+	}
+
+	m, err := typutil.As[map[string]any](Account{ID: 7, Password: "secret"})
+	if err != nil {
+		t.Errorf("struct to map failed: %s", err)
+	} else if _, found := m["password"]; found || m["account_id"] != 7 {
+		t.Errorf("unexpected value %v", m)
+	}
+
+	// JSON encoding is unaffected
+	buf, _ := json.Marshal(Account{ID: 7, Password: "secret", Internal: "yes"})
+	if string(buf) != `{"id":7,"password":"secret"}` {
+		t.Errorf("unexpected JSON %s", buf)
+	}
+}
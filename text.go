@@ -0,0 +1,96 @@
+package typutil
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+)
+
+var (
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+	textMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()
+	stringerType        = reflect.TypeFor[fmt.Stringer]()
+)
+
+// isTextSource returns true if values of type t can be passed to UnmarshalText
+func isTextSource(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.Uint8
+	}
+	return false
+}
+
+// makeAssignFromText returns an assignFunc that calls dst's UnmarshalText
+// method with the string or []byte value of src.
+func (c *Converter) makeAssignFromText(dstt, srct reflect.Type) (assignFunc, error) {
+	if srct.Kind() == reflect.Interface {
+		// the concrete source type is only known at runtime
+		return c.makeAssignAnyToRuntime(dstt, srct), nil
+	}
+
+	validator := c.getValidatorForType(dstt)
+	isString := srct.Kind() == reflect.String
+
+	f := func(dst, src reflect.Value) error {
+		if !dst.CanAddr() {
+			return ErrDestinationNotAddressable
+		}
+		var text []byte
+		if isString {
+			text = []byte(src.String())
+		} else {
+			text = src.Bytes()
+		}
+		err := dst.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText(text)
+		if err != nil {
+			return err
+		}
+
+		return validator.validate(c, dst)
+	}
+	return f, nil
+}
+
+// makeAssignToText returns an assignFunc that sets the string dst using the
+// MarshalText or String method of src, or nil if srct implements neither.
+// MarshalText is preferred, and methods declared on the pointer receiver are
+// also used.
+func (c *Converter) makeAssignToText(dstt, srct reflect.Type) assignFunc {
+	if srct.Kind() == reflect.Interface {
+		// resolved at runtime from the concrete type
+		return nil
+	}
+
+	for _, intf := range []reflect.Type{textMarshalerType, stringerType} {
+		if srct.Implements(intf) {
+			return makeTextFunc(func(src reflect.Value) any { return src.Interface() })
+		}
+		if reflect.PointerTo(srct).Implements(intf) {
+			return makeTextFunc(func(src reflect.Value) any {
+				srcptr := reflect.New(srct)
+				srcptr.Elem().Set(src)
+				return srcptr.Interface()
+			})
+		}
+	}
+	return nil
+}
+
+func makeTextFunc(textIntf func(src reflect.Value) any) assignFunc {
+	return func(dst, src reflect.Value) error {
+		switch v := textIntf(src).(type) {
+		case encoding.TextMarshaler:
+			text, err := v.MarshalText()
+			if err != nil {
+				return err
+			}
+			dst.SetString(string(text))
+		case fmt.Stringer:
+			dst.SetString(v.String())
+		}
+		return nil
+	}
+}
@@ -0,0 +1,127 @@
+package typutil_test
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net/netip"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/KarpelesLab/typutil"
+)
+
+// userID is stored as a number but exchanged as "usr-<n>"
+type userID int64
+
+func (u userID) MarshalText() ([]byte, error) {
+	return []byte("usr-" + strconv.FormatInt(int64(u), 10)), nil
+}
+
+func (u *userID) UnmarshalText(b []byte) error {
+	s, ok := strings.CutPrefix(string(b), "usr-")
+	if !ok {
+		return errors.New("invalid user id")
+	}
+	n, ok := typutil.AsInt(s)
+	if !ok {
+		return errors.New("invalid user id")
+	}
+	*u = userID(n)
+	return nil
+}
+
+type level int
+
+func (l level) String() string {
+	return [...]string{"debug", "info", "warn"}[l]
+}
+
+// scanned implements both Scan and UnmarshalText, Scan must win
+type scanned string
+
+func (s *scanned) Scan(v any) error {
+	*s = scanned("scan:" + fmt.Sprint(v))
+	return nil
+}
+
+func (s *scanned) UnmarshalText(b []byte) error {
+	*s = scanned("text:" + string(b))
+	return nil
+}
+
+func TestAssignTextUnmarshaler(t *testing.T) {
+	addr, err := typutil.As[netip.Addr]("192.168.1.1")
+	if err != nil {
+		t.Errorf("string to netip.Addr failed: %s", err)
+	} else if addr != netip.MustParseAddr("192.168.1.1") {
+		t.Errorf("unexpected value %s", addr)
+	}
+
+	if _, err := typutil.As[netip.Addr]("not an ip"); err == nil {
+		t.Errorf("expected error for invalid address")
+	}
+
+	n, err := typutil.As[*big.Int]([]byte("123456789012345678901234567890"))
+	if err != nil {
+		t.Errorf("[]byte to *big.Int failed: %s", err)
+	} else if n.String() != "123456789012345678901234567890" {
+		t.Errorf("unexpected value %s", n)
+	}
+
+	id, err := typutil.As[userID]("usr-42")
+	if err != nil || id != 42 {
+		t.Errorf("string to userID: got %d (err=%v)", id, err)
+	}
+
+	// numbers do not go through UnmarshalText
+	id, err = typutil.As[userID](42)
+	if err != nil || id != 42 {
+		t.Errorf("int to userID: got %d (err=%v)", id, err)
+	}
+
+	s, err := typutil.As[scanned]("x")
+	if err != nil || s != "scan:x" {
+		t.Errorf("expected Scan to take precedence, got %q (err=%v)", s, err)
+	}
+
+	type User struct {
+		ID   userID
+		Addr *netip.Addr
+	}
+	u, err := typutil.As[User](map[string]any{"ID": "usr-7", "Addr": "::1"})
+	if err != nil {
+		t.Errorf("map to struct failed: %s", err)
+	} else if u.ID != 7 || u.Addr == nil || *u.Addr != netip.IPv6Loopback() {
+		t.Errorf("unexpected value %+v", u)
+	}
+}
+
+func TestAssignTextMarshaler(t *testing.T) {
+	s, err := typutil.As[string](netip.MustParseAddr("10.0.0.1"))
+	if err != nil || s != "10.0.0.1" {
+		t.Errorf("netip.Addr to string: got %q (err=%v)", s, err)
+	}
+
+	s, err = typutil.As[string](big.NewInt(1234))
+	if err != nil || s != "1234" {
+		t.Errorf("*big.Int to string: got %q (err=%v)", s, err)
+	}
+
+	s, err = typutil.As[string](userID(5))
+	if err != nil || s != "usr-5" {
+		t.Errorf("userID to string: got %q (err=%v)", s, err)
+	}
+
+	s, err = typutil.As[string](level(2))
+	if err != nil || s != "warn" {
+		t.Errorf("level to string: got %q (err=%v)", s, err)
+	}
+
+	var m map[string]string
+	err = typutil.Assign(&m, map[string]any{"id": userID(3)})
+	if err != nil || m["id"] != "usr-3" {
+		t.Errorf("map of any to map of strings: got %v (err=%v)", m, err)
+	}
+}
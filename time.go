@@ -0,0 +1,210 @@
+package typutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+)
+
+var (
+	timeType       = reflect.TypeFor[time.Time]()
+	jsonNumberType = reflect.TypeFor[json.Number]()
+)
+
+// defaultTimeLayouts are the layouts used to parse times when none were set
+// with TimeLayouts. The first layout is also used to format times as strings.
+var defaultTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// TimeLayouts is a functional option for NewConverter that sets the layouts
+// used to parse strings into time.Time values. Layouts are tried in order, and
+// the first one is also used when converting a time.Time into a string.
+//
+// The default layouts are RFC 3339 (with optional fractional seconds), followed
+// by "2006-01-02T15:04:05", "2006-01-02 15:04:05" and "2006-01-02".
+//
+// Example:
+//
+//	conv := NewConverter(TimeLayouts(time.RFC1123, time.RFC3339))
+func TimeLayouts(layouts ...string) converterOption {
+	return func(c *Converter) {
+		c.timeLayouts = layouts
+	}
+}
+
+// TimeLocation is a functional option for NewConverter that sets the time zone
+// used for time conversions. Strings that do not include a time zone are parsed
+// in this location, times created from unix timestamps are returned in it, and
+// times converted to strings are formatted in it. The default is UTC.
+func TimeLocation(loc *time.Location) converterOption {
+	return func(c *Converter) {
+		c.timeLoc = loc
+	}
+}
+
+// UnixMilli is a functional option for NewConverter that makes numbers
+// converted to or from time.Time values be treated as unix timestamps in
+// milliseconds instead of seconds.
+func UnixMilli(c *Converter) {
+	c.unixMilli = true
+}
+
+func (c *Converter) getTimeLayouts() []string {
+	if len(c.timeLayouts) == 0 {
+		return defaultTimeLayouts
+	}
+	return c.timeLayouts
+}
+
+func (c *Converter) getTimeLocation() *time.Location {
+	if c.timeLoc == nil {
+		return time.UTC
+	}
+	return c.timeLoc
+}
+
+// parseTime parses s using the converter's layouts. Strings that look like numbers
+// are accepted as unix timestamps.
+func (c *Converter) parseTime(s string) (time.Time, error) {
+	loc := c.getTimeLocation()
+
+	var firstErr error
+	for _, layout := range c.getTimeLayouts() {
+		t, err := time.ParseInLocation(layout, s, loc)
+		if err == nil {
+			return t, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	if n, ok := c.asNumber(s); ok {
+		return c.unixTime(n)
+	}
+	return time.Time{}, fmt.Errorf("%w: failed to parse %q as time: %w", ErrSyntax, s, firstErr)
+}
+
+// unixTime returns the time for the unix timestamp n, which is an int64, uint64 or float64
+func (c *Converter) unixTime(n any) (time.Time, error) {
+	var t time.Time
+	switch x := n.(type) {
+	case int64:
+		if c.unixMilli {
+			t = time.UnixMilli(x)
+		} else {
+			t = time.Unix(x, 0)
+		}
+	case uint64:
+		if x > math.MaxInt64 {
+			return t, fmt.Errorf("%w: %d is not a valid unix timestamp", ErrOverflow, x)
+		}
+		return c.unixTime(int64(x))
+	case float64:
+		if c.unixMilli {
+			x /= 1e3
+		}
+		if math.IsNaN(x) || x < math.MinInt64 || x >= math.MaxInt64 {
+			return t, fmt.Errorf("%w: %v is not a valid unix timestamp", ErrOverflow, x)
+		}
+		sec, frac := math.Modf(x)
+		t = time.Unix(int64(sec), int64(math.Round(frac*1e9)))
+	default:
+		return t, fmt.Errorf("%w: cannot convert %T to time", ErrAssignImpossible, n)
+	}
+	return t.In(c.getTimeLocation()), nil
+}
+
+func (c *Converter) makeAssignToTime(dstt, srct reflect.Type) (assignFunc, error) {
+	if srct == jsonNumberType {
+		f := func(dst, src reflect.Value) error {
+			n, ok := c.asNumber(src.String())
+			if !ok {
+				return fmt.Errorf("%w: failed to parse %q as time", ErrSyntax, src.String())
+			}
+			t, err := c.unixTime(n)
+			if err != nil {
+				return err
+			}
+			dst.Set(reflect.ValueOf(t))
+			return nil
+		}
+		return f, nil
+	}
+
+	switch srct.Kind() {
+	case reflect.String:
+		f := func(dst, src reflect.Value) error {
+			t, err := c.parseTime(src.String())
+			if err != nil {
+				return err
+			}
+			dst.Set(reflect.ValueOf(t))
+			return nil
+		}
+		return f, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		f := func(dst, src reflect.Value) error {
+			n, _ := c.numberOf(src)
+			t, err := c.unixTime(n)
+			if err != nil {
+				return err
+			}
+			dst.Set(reflect.ValueOf(t))
+			return nil
+		}
+		return f, nil
+	case reflect.Interface:
+		// perform this runtime
+		return c.makeAssignAnyToRuntime(dstt, srct), nil
+	}
+	return nil, fmt.Errorf("%w: unsupported type %s to time", ErrAssignImpossible, srct)
+}
+
+// makeAssignFromTime returns a function converting a time.Time into dstt, or nil if
+// time values do not have a specific conversion to dstt.
+func (c *Converter) makeAssignFromTime(dstt, srct reflect.Type) assignFunc {
+	switch dstt.Kind() {
+	case reflect.String:
+		layout := c.getTimeLayouts()[0]
+		loc := c.getTimeLocation()
+		return func(dst, src reflect.Value) error {
+			dst.SetString(src.Interface().(time.Time).In(loc).Format(layout))
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(dst, src reflect.Value) error {
+			return c.setInt(dst, c.unixValue(src.Interface().(time.Time)))
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(dst, src reflect.Value) error {
+			return c.setUint(dst, c.unixValue(src.Interface().(time.Time)))
+		}
+	case reflect.Float32, reflect.Float64:
+		return func(dst, src reflect.Value) error {
+			t := src.Interface().(time.Time)
+			f := float64(t.UnixNano()) / 1e9
+			if c.unixMilli {
+				f = float64(t.UnixNano()) / 1e6
+			}
+			dst.SetFloat(f)
+			return nil
+		}
+	}
+	return nil
+}
+
+// unixValue returns t as a unix timestamp in seconds or milliseconds
+func (c *Converter) unixValue(t time.Time) int64 {
+	if c.unixMilli {
+		return t.UnixMilli()
+	}
+	return t.Unix()
+}
@@ -0,0 +1,144 @@
+package typutil_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/KarpelesLab/typutil"
+)
+
+func TestAsTimeFromString(t *testing.T) {
+	expected := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []string{
+		"2024-01-02T03:04:05Z",
+		"2024-01-02T05:04:05+02:00",
+		"2024-01-02T03:04:05",
+		"2024-01-02 03:04:05",
+	}
+
+	for _, s := range tests {
+		v, err := typutil.As[time.Time](s)
+		if err != nil {
+			t.Errorf("As[time.Time](%q) failed: %s", s, err)
+		} else if !v.Equal(expected) {
+			t.Errorf("As[time.Time](%q) = %s, expected %s", s, v, expected)
+		}
+	}
+
+	v, err := typutil.As[time.Time]("2024-01-02")
+	if err != nil {
+		t.Errorf("date only parse failed: %s", err)
+	} else if !v.Equal(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected value %s", v)
+	}
+
+	if _, err := typutil.As[time.Time]("not a time"); err == nil {
+		t.Errorf("expected error for invalid time string")
+	}
+}
+
+func TestAsTimeFromNumber(t *testing.T) {
+	expected := time.Unix(1700000000, 0)
+
+	for _, in := range []any{1700000000, int64(1700000000), uint32(1700000000), 1700000000.0, json.Number("1700000000"), "1700000000"} {
+		v, err := typutil.As[time.Time](in)
+		if err != nil {
+			t.Errorf("As[time.Time](%v) failed: %s", in, err)
+		} else if !v.Equal(expected) {
+			t.Errorf("As[time.Time](%v) = %s, expected %s", in, v, expected)
+		} else if v.Location() != time.UTC {
+			t.Errorf("expected UTC location, got %s", v.Location())
+		}
+	}
+
+	v, err := typutil.As[time.Time](1700000000.5)
+	if err != nil || !v.Equal(expected.Add(500*time.Millisecond)) {
+		t.Errorf("fractional timestamp: got %s (err=%v)", v, err)
+	}
+
+	conv := typutil.NewConverter(typutil.UnixMilli)
+	v, err = typutil.AsWith[time.Time](conv, int64(1700000000123))
+	if err != nil || !v.Equal(expected.Add(123*time.Millisecond)) {
+		t.Errorf("millisecond timestamp: got %s (err=%v)", v, err)
+	}
+}
+
+func TestTimeToValues(t *testing.T) {
+	tm := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	s, err := typutil.As[string](tm)
+	if err != nil || s != "2024-01-02T03:04:05Z" {
+		t.Errorf("time to string: got %q (err=%v)", s, err)
+	}
+
+	i, err := typutil.As[int64](tm)
+	if err != nil || i != tm.Unix() {
+		t.Errorf("time to int64: got %d (err=%v)", i, err)
+	}
+
+	conv := typutil.NewConverter(typutil.UnixMilli, typutil.TimeLayouts(time.RFC1123))
+	i, err = typutil.AsWith[int64](conv, tm)
+	if err != nil || i != tm.UnixMilli() {
+		t.Errorf("time to int64 (ms): got %d (err=%v)", i, err)
+	}
+	s, err = typutil.AsWith[string](conv, tm)
+	if err != nil || s != tm.Format(time.RFC1123) {
+		t.Errorf("time to string (RFC1123): got %q (err=%v)", s, err)
+	}
+
+	// int32 cannot hold a millisecond timestamp
+	if _, err := typutil.AsWith[int32](conv, tm); err == nil {
+		t.Errorf("expected overflow error")
+	}
+}
+
+func TestTimeLocation(t *testing.T) {
+	loc := time.FixedZone("UTC+9", 9*3600)
+	conv := typutil.NewConverter(typutil.TimeLocation(loc))
+
+	v, err := typutil.AsWith[time.Time](conv, "2024-01-02 03:04:05")
+	if err != nil {
+		t.Errorf("parse failed: %s", err)
+	} else if !v.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, loc)) {
+		t.Errorf("unexpected value %s", v)
+	}
+
+	v, err = typutil.AsWith[time.Time](conv, 0)
+	if err != nil {
+		t.Errorf("unix parse failed: %s", err)
+	} else if v.Location() != loc || v.Hour() != 9 {
+		t.Errorf("expected time in UTC+9, got %s", v)
+	}
+
+	s, err := typutil.AsWith[string](conv, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	if err != nil || s != "2024-01-02T12:04:05+09:00" {
+		t.Errorf("unexpected value %q (err=%v)", s, err)
+	}
+}
+
+func TestTimeInStruct(t *testing.T) {
+	type Event struct {
+		Name string
+		At   time.Time
+		End  *time.Time
+	}
+
+	e, err := typutil.As[Event](map[string]any{"Name": "launch", "At": "2024-01-02T03:04:05Z", "End": 1704164645})
+	if err != nil {
+		t.Errorf("map to struct failed: %s", err)
+		return
+	}
+	if !e.At.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("unexpected At %s", e.At)
+	}
+	if e.End == nil || !e.End.Equal(e.At) {
+		t.Errorf("unexpected End %v", e.End)
+	}
+
+	var m map[string]string
+	if err := typutil.Assign(&m, map[string]time.Time{"at": e.At}); err != nil || m["at"] != "2024-01-02T03:04:05Z" {
+		t.Errorf("map of times to map of strings: got %v (err=%v)", m, err)
+	}
+}
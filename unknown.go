@@ -0,0 +1,67 @@
+package typutil
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DisallowUnknownFields is a functional option for NewConverter that makes
+// conversions from maps to structs fail when the map has keys that do not
+// match any field, like json.Decoder.DisallowUnknownFields.
+//
+// The conversion still processes all the keys, so that the returned
+// *UnknownFieldsError lists every unknown key, including those found in nested
+// structs, slices and maps.
+func DisallowUnknownFields(c *Converter) {
+	c.disallowUnknown = true
+}
+
+// UnknownFieldsError is returned when DisallowUnknownFields is set and the
+// source of a conversion has keys not matching any field of the destination.
+// It wraps ErrUnknownField.
+type UnknownFieldsError struct {
+	// Fields holds the path of each unknown key, such as "items[3].pricee"
+	Fields []string
+}
+
+// Error returns a message listing all the unknown fields.
+func (e *UnknownFieldsError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrUnknownField, strings.Join(e.Fields, ", "))
+}
+
+// Unwrap returns ErrUnknownField, so errors.Is can be used to detect unknown fields.
+func (e *UnknownFieldsError) Unwrap() error {
+	return ErrUnknownField
+}
+
+// appendUnknown appends to list the unknown fields of err, prefixed with
+// prefix, and returns false if err is not an *UnknownFieldsError
+func appendUnknown(list []string, prefix string, err error) ([]string, bool) {
+	uerr, ok := err.(*UnknownFieldsError)
+	if !ok {
+		return list, false
+	}
+	for _, f := range uerr.Fields {
+		list = append(list, joinPath(prefix, f))
+	}
+	return list, true
+}
+
+// unknownError returns an *UnknownFieldsError for the given fields, or nil
+func unknownError(fields []string) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	slices.Sort(fields)
+	return &UnknownFieldsError{Fields: fields}
+}
+
+// joinPath joins a parent path with the path of a child, which either starts
+// with an index ("[3]") or a field name
+func joinPath(parent, child string) string {
+	if parent == "" || child == "" || strings.HasPrefix(child, "[") {
+		return parent + child
+	}
+	return parent + "." + child
+}
@@ -0,0 +1,62 @@
+package typutil_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/KarpelesLab/typutil"
+)
+
+func TestDisallowUnknownFields(t *testing.T) {
+	type Item struct {
+		Name  string
+		Price float64
+	}
+	type Order struct {
+		ID       int
+		Items    []Item
+		Shipping struct {
+			Address string
+		}
+		Extras map[string]Item
+	}
+
+	src := map[string]any{
+		"ID":  1,
+		"Foo": true,
+		"Items": []any{
+			map[string]any{"Name": "a", "Price": 1},
+			map[string]any{"Name": "b", "Pricee": 2},
+		},
+		"Shipping": map[string]any{"Adress": "here"},
+		"Extras":   map[string]any{"gift": map[string]any{"Colour": "red"}},
+	}
+
+	// unknown keys are ignored by default
+	if _, err := typutil.As[Order](src); err != nil {
+		t.Errorf("unexpected error %s", err)
+	}
+
+	conv := typutil.NewConverter(typutil.DisallowUnknownFields)
+	_, err := typutil.AsWith[Order](conv, src)
+	if !errors.Is(err, typutil.ErrUnknownField) {
+		t.Errorf("expected ErrUnknownField, got %v", err)
+		return
+	}
+	var uerr *typutil.UnknownFieldsError
+	if !errors.As(err, &uerr) {
+		t.Errorf("expected *UnknownFieldsError, got %T", err)
+		return
+	}
+	expected := []string{"Extras[gift].Colour", "Foo", "Items[1].Pricee", "Shipping.Adress"}
+	if !slices.Equal(uerr.Fields, expected) {
+		t.Errorf("unexpected unknown fields %v, expected %v", uerr.Fields, expected)
+	}
+
+	// case insensitive matches are not unknown
+	o, err := typutil.AsWith[Order](conv, map[string]any{"id": 2})
+	if err != nil || o.ID != 2 {
+		t.Errorf("unexpected value %+v (err=%v)", o, err)
+	}
+}
@@ -12,10 +12,38 @@ type validatorObject struct {
 	arg reflect.Type
 }
 
-var (
-	validators   = map[string]*validatorObject{}
-	validatorsLk sync.RWMutex
-)
+// validatorRegistry holds named validators. A registry with a parent falls back
+// to it for names it does not define itself.
+type validatorRegistry struct {
+	parent *validatorRegistry
+	m      map[string]*validatorObject
+	lk     sync.RWMutex
+}
+
+// globalValidators is the registry used by SetValidator and SetValidatorArgs
+var globalValidators = newValidatorRegistry(nil)
+
+func newValidatorRegistry(parent *validatorRegistry) *validatorRegistry {
+	return &validatorRegistry{parent: parent, m: make(map[string]*validatorObject)}
+}
+
+func (r *validatorRegistry) set(name string, o *validatorObject) {
+	r.lk.Lock()
+	defer r.lk.Unlock()
+
+	r.m[name] = o
+}
+
+func (r *validatorRegistry) get(name string) (*validatorObject, bool) {
+	r.lk.RLock()
+	o, ok := r.m[name]
+	r.lk.RUnlock()
+
+	if !ok && r.parent != nil {
+		return r.parent.get(name)
+	}
+	return o, ok
+}
 
 // A validator function takes one argument (the value being validated) and returns either nil or an error.
 // If the function accepts a modifiable value (like a pointer), it can potentially modify the value during validation.
@@ -71,10 +99,7 @@ func SetValidator[T any](validator string, fnc func(T) error) {
 	vfnc := reflect.ValueOf(fnc)
 	argt := reflect.TypeFor[T]()
 
-	validatorsLk.Lock()
-	defer validatorsLk.Unlock()
-
-	validators[validator] = &validatorObject{fnc: vfnc, arg: argt}
+	globalValidators.set(validator, &validatorObject{fnc: vfnc, arg: argt})
 }
 
 // SetValidatorArgs registers a validation function that may accept additional arguments.
@@ -110,6 +135,20 @@ func SetValidator[T any](validator string, fnc func(T) error) {
 //   - fnc is not a function
 //   - fnc does not accept at least one argument
 func SetValidatorArgs(validator string, fnc any) {
+	globalValidators.set(validator, newValidatorObject(fnc))
+}
+
+// SetValidator registers a validation function on this converter only. It
+// accepts the same functions as SetValidatorArgs; validators registered this way
+// take precedence over the globally registered ones with the same name.
+//
+// Validators already resolved for a given struct type are cached, so validators
+// should be registered before the converter is first used.
+func (c *Converter) SetValidator(validator string, fnc any) {
+	c.validators.set(validator, newValidatorObject(fnc))
+}
+
+func newValidatorObject(fnc any) *validatorObject {
 	vfnc := reflect.ValueOf(fnc)
 	if vfnc.Kind() != reflect.Func {
 		panic("not a function")
@@ -120,11 +159,11 @@ func SetValidatorArgs(validator string, fnc any) {
 	}
 	argt := t.In(0)
 
-	validators[validator] = &validatorObject{fnc: vfnc, arg: argt}
+	return &validatorObject{fnc: vfnc, arg: argt}
 }
 
 // getValidators returns the validator objects for a given validator tag value. Multiple validators can be defined
-func getValidators(s string) ([]*validatorObject, [][]reflect.Value, error) {
+func (c *Converter) getValidators(s string) ([]*validatorObject, [][]reflect.Value, error) {
 	if s == "" {
 		return nil, nil, nil
 	}
@@ -132,9 +171,6 @@ func getValidators(s string) ([]*validatorObject, [][]reflect.Value, error) {
 	res := make([]*validatorObject, 0, len(a))
 	res2 := make([][]reflect.Value, 0, len(a))
 
-	validatorsLk.RLock()
-	defer validatorsLk.RUnlock()
-
 	for _, v := range a {
 		p := strings.IndexByte(v, '=')
 		a := ""
@@ -143,12 +179,12 @@ func getValidators(s string) ([]*validatorObject, [][]reflect.Value, error) {
 			a = v[p+1:]
 			v = v[:p]
 		}
-		o, ok := validators[v]
+		o, ok := c.validators.get(v)
 		if !ok {
 			return res, res2, fmt.Errorf("validator not found: %s", a)
 		}
 		res = append(res, o)
-		res2 = append(res2, o.convertArgs(a))
+		res2 = append(res2, o.convertArgs(c, a))
 	}
 
 	return res, res2, nil
@@ -157,26 +193,22 @@ func getValidators(s string) ([]*validatorObject, [][]reflect.Value, error) {
 type fieldValidator struct {
 	fld  int    // field index
 	name string // field name
+	path string // field name used in error paths
 	vals []*validatorObject
 	args [][]reflect.Value // extra validator param, if any
 }
 
 type structValidator []*fieldValidator
 
-var (
-	validatorCache   = make(map[reflect.Type]structValidator)
-	validatorCacheLk sync.Mutex
-)
-
-func getValidatorForType(t reflect.Type) structValidator {
-	validatorCacheLk.Lock()
-	defer validatorCacheLk.Unlock()
+func (c *Converter) getValidatorForType(t reflect.Type) structValidator {
+	c.validatorCacheLk.Lock()
+	defer c.validatorCacheLk.Unlock()
 
 	if t.Kind() != reflect.Struct {
 		return nil
 	}
 
-	val, ok := validatorCache[t]
+	val, ok := c.validatorCache[t]
 	if ok {
 		return val
 	}
@@ -184,7 +216,7 @@ func getValidatorForType(t reflect.Type) structValidator {
 	n := t.NumField()
 	for i := 0; i < n; i++ {
 		f := t.Field(i)
-		vals, args, err := getValidators(f.Tag.Get("validator"))
+		vals, args, err := c.getValidators(f.Tag.Get("validator"))
 		if err != nil {
 			// skip
 			continue
@@ -192,24 +224,60 @@ func getValidatorForType(t reflect.Type) structValidator {
 		if len(vals) == 0 {
 			continue
 		}
-		val = append(val, &fieldValidator{fld: i, name: f.Name, vals: vals, args: args})
+		path, _, _ := c.fieldName(f)
+		val = append(val, &fieldValidator{fld: i, name: f.Name, path: path, vals: vals, args: args})
+	}
+	if c.validatorCache == nil {
+		c.validatorCache = make(map[reflect.Type]structValidator)
 	}
-	validatorCache[t] = val
+	c.validatorCache[t] = val
 	return val
 }
 
-func (sv structValidator) validate(val reflect.Value) error {
-	var err error
+func (sv structValidator) validate(c *Converter, val reflect.Value) error {
+	var errs MultiError
 	for _, vd := range sv {
-		f := val.Field(vd.fld).Addr()
+		fv := val.Field(vd.fld)
+		f := fv.Addr()
 		for n, sub := range vd.vals {
-			err = sub.runReflectValue(f, vd.args[n])
-			if err != nil {
-				return fmt.Errorf("on field %s: %w", vd.name, err)
+			err := sub.runReflectValue(c, f, vd.args[n])
+			if err == nil {
+				continue
 			}
+			if !c.collectErrors {
+				return &validationError{field: vd.name, err: err}
+			}
+			cerr := &ConversionError{Path: vd.path, Src: fv.Type(), Dst: fv.Type(), Reason: ReasonValidation, Err: err}
+			if fv.CanInterface() {
+				cerr.Value = fv.Interface()
+			}
+			errs = append(errs, cerr)
+			// the other validators of this field are not run
+			break
 		}
 	}
-	return nil
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	default:
+		return errs
+	}
+}
+
+// validationError is returned when a struct field fails validation
+type validationError struct {
+	field string
+	err   error
+}
+
+func (e *validationError) Error() string {
+	return fmt.Sprintf("on field %s: %s", e.field, e.err)
+}
+
+func (e *validationError) Unwrap() error {
+	return e.err
 }
 
 // Validate checks if a struct meets all validation rules defined in its field tags.
@@ -241,6 +309,12 @@ func (sv structValidator) validate(val reflect.Value) error {
 // Validations are applied in the order they appear in the tag, from left to right.
 // If a field has no validator tag or the tag is empty, it is not validated.
 func Validate(obj any) error {
+	return defaultConverter.Validate(obj)
+}
+
+// Validate checks obj against the validators known to this converter. See the
+// package-level Validate for details.
+func (c *Converter) Validate(obj any) error {
 	v := reflect.ValueOf(obj)
 	if v.Kind() != reflect.Pointer {
 		return ErrStructPtrRequired
@@ -252,12 +326,12 @@ func Validate(obj any) error {
 		return ErrStructPtrRequired
 	}
 
-	return getValidatorForType(v.Type()).validate(v)
+	return c.getValidatorForType(v.Type()).validate(c, v)
 }
 
-func (v *validatorObject) runReflectValue(val reflect.Value, args []reflect.Value) error {
+func (v *validatorObject) runReflectValue(c *Converter, val reflect.Value, args []reflect.Value) error {
 	valT := reflect.New(v.arg)
-	err := AssignReflect(valT, val)
+	err := c.AssignReflect(valT, val)
 	if err != nil {
 		return err
 	}
@@ -269,7 +343,7 @@ func (v *validatorObject) runReflectValue(val reflect.Value, args []reflect.Valu
 	return res[0].Interface().(error)
 }
 
-func (v *validatorObject) convertArgs(args string) []reflect.Value {
+func (v *validatorObject) convertArgs(c *Converter, args string) []reflect.Value {
 	t := v.fnc.Type()
 	if t.NumIn() <= 1 {
 		// 0 shouldn't happen, 1 means there are no extra args to take into account
@@ -286,7 +360,7 @@ func (v *validatorObject) convertArgs(args string) []reflect.Value {
 	for i := 0; i < extraCnt; i++ {
 		argt := t.In(i + 1)
 		v := reflect.New(argt).Elem()
-		AssignReflect(v, reflect.ValueOf(argsArray[i]))
+		c.AssignReflect(v, reflect.ValueOf(argsArray[i]))
 		res = append(res, v)
 	}
 	return res